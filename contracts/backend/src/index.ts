@@ -165,12 +165,14 @@ app.get("/state/:contractAddress", async (req: Request, res: Response) => {
     const owner = await contract.owner();
     const bettingEnd = await contract.bettingEnd();
     const settled = await contract.settled();
+    const voided = await contract.voided();
     const totalPool = await contract.totalPool();
 
     res.status(200).json({
       owner: owner,
       bettingEnd: new Date(Number(bettingEnd) * 1000).toISOString(),
       settled: settled,
+      voided: voided,
       totalPool: ethers.formatEther(totalPool),
     });
   } catch (error) {
@@ -7,6 +7,7 @@ pragma solidity ^0.8.20;
  * Outcomes: 0 = Team A win, 1 = Draw, 2 = Team B win.
  * Bets in HBAR; 2% fee on total real pool.
  * Initial odds seeded with virtual pools (do not affect real payouts).
+ * The owner can void an unsettled matchup, after which every stake is refunded in full.
  * Uses modern .call for HBAR transfers to avoid deprecation warnings.
  */
 contract FPLMatchupBet {
@@ -31,10 +32,13 @@ contract FPLMatchupBet {
     bool public settled;
     uint8 public winner; // 0=A, 1=Draw, 2=B
     uint256 public netPool; // Real total minus 2% fee
+    bool public voided; // Cancelled by the owner; stakes are refunded instead of claimed
 
     event BetPlaced(address indexed bettor, uint8 outcome, uint256 amount);
     event Settled(uint8 winner);
     event Claimed(address indexed claimer, uint256 payout);
+    event Voided();
+    event Refunded(address indexed bettor, uint256 amount);
 
     constructor(uint256 _bettingEnd, uint256 _virtualPoolA, uint256 _virtualPoolDraw, uint256 _virtualPoolB) {
         owner = msg.sender;
@@ -64,6 +68,7 @@ contract FPLMatchupBet {
     function bet(uint8 outcome) external payable {
         require(block.timestamp < bettingEnd, "Betting closed");
         require(!settled, "Already settled");
+        require(!voided, "Voided");
         require(msg.value > 0, "No HBAR sent");
         require(outcome <= 2, "Invalid outcome");
 
@@ -114,6 +119,7 @@ contract FPLMatchupBet {
      */
     function settle(uint8 _winner) external onlyOwner {
         require(!settled, "Already settled");
+        require(!voided, "Voided");
         require(block.timestamp >= bettingEnd, "Betting not ended");
         require(_winner <= 2, "Invalid winner");
 
@@ -174,6 +180,42 @@ contract FPLMatchupBet {
         emit Claimed(msg.sender, payout);
     }
 
+    /**
+     * @dev Owner cancels the matchup, e.g. when its gameweek is postponed. Allowed at any
+     * time before settlement; no fee is taken and every stake becomes refundable.
+     */
+    function void() external onlyOwner {
+        require(!settled, "Already settled");
+        require(!voided, "Already voided");
+
+        voided = true;
+
+        emit Voided();
+    }
+
+    /**
+     * @dev Returns a bettor's full stake from a voided matchup. The owner can push refunds
+     * to bettors, or bettors can pull their own.
+     *
+     * HEDERA NOTE: Scales the stake back to tinybars before transfer.
+     */
+    function refund(address bettor) external {
+        require(voided, "Not voided");
+        require(msg.sender == owner || msg.sender == bettor, "Not owner or bettor");
+        require(userAmount[bettor] > 0, "No stake");
+
+        uint256 amount = userAmount[bettor];
+
+        userAmount[bettor] = 0; // Prevent re-refund
+
+        // Scale back to tinybars for Hedera transfer (divide by 10^10)
+        uint256 amountInTinybars = amount / 1e10;
+        (bool sent, ) = payable(bettor).call{value: amountInTinybars}("");
+        require(sent, "Refund transfer failed");
+
+        emit Refunded(bettor, amount);
+    }
+
     // Fallback to accept accidental HBAR
     receive() external payable {}
 }
@@ -315,6 +315,77 @@ describe("FPLMatchupBet", function () {
     });
   });
 
+  describe("Voiding and Refunds", function () {
+    beforeEach(async function () {
+      await contract.connect(bettor1).bet(0, { value: ethers.parseEther("10") });
+      await contract.connect(bettor2).bet(2, { value: ethers.parseEther("15") });
+    });
+
+    it("Should allow owner to void before settlement", async function () {
+      await expect(contract.void()).to.emit(contract, "Voided");
+      expect(await contract.voided()).to.be.true;
+    });
+
+    it("Should revert if non-owner tries to void", async function () {
+      await expect(contract.connect(bettor1).void()).to.be.revertedWith("Not owner");
+    });
+
+    it("Should revert if voiding a settled matchup", async function () {
+      await time.increase(ONE_WEEK + 1);
+      await contract.settle(0);
+      await expect(contract.void()).to.be.revertedWith("Already settled");
+    });
+
+    it("Should revert if already voided", async function () {
+      await contract.void();
+      await expect(contract.void()).to.be.revertedWith("Already voided");
+    });
+
+    it("Should block betting and settling once voided", async function () {
+      await contract.void();
+      await expect(
+        contract.connect(bettor3).bet(1, { value: ethers.parseEther("5") })
+      ).to.be.revertedWith("Voided");
+
+      await time.increase(ONE_WEEK + 1);
+      await expect(contract.settle(0)).to.be.revertedWith("Voided");
+    });
+
+    it("Should let the owner refund a bettor's full stake", async function () {
+      await contract.void();
+
+      const balanceBefore = await ethers.provider.getBalance(bettor1.address);
+      await expect(contract.refund(bettor1.address))
+        .to.emit(contract, "Refunded")
+        .withArgs(bettor1.address, ethers.parseEther("10") * BigInt(1e10));
+      const balanceAfter = await ethers.provider.getBalance(bettor1.address);
+
+      expect(balanceAfter).to.equal(balanceBefore + ethers.parseEther("10"));
+    });
+
+    it("Should let a bettor refund themselves", async function () {
+      await contract.void();
+      await expect(contract.connect(bettor2).refund(bettor2.address)).to.emit(contract, "Refunded");
+    });
+
+    it("Should revert if a bettor refunds someone else", async function () {
+      await contract.void();
+      await expect(contract.connect(bettor2).refund(bettor1.address)).to.be.revertedWith(
+        "Not owner or bettor"
+      );
+    });
+
+    it("Should prevent double refunds", async function () {
+      await contract.void();
+      await contract.refund(bettor1.address);
+      await expect(contract.refund(bettor1.address)).to.be.revertedWith("No stake");
+    });
+
+    it("Should revert if refunding before voiding", async function () {
+      await expect(contract.refund(bettor1.address)).to.be.revertedWith("Not voided");
+    });
+  });
+
   describe("Edge Cases", function () {
     it("Should handle draw outcome correctly", async function () {
       await contract.connect(bettor1).bet(1, { value: ethers.parseEther("10") });
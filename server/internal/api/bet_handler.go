@@ -3,31 +3,51 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/divin3circle/fplduel/server/internal/services"
 	"github.com/divin3circle/fplduel/server/internal/stores"
 	"github.com/divin3circle/fplduel/server/internal/utils"
 )
 
+// CreateBetRequest carries no odds: the bet is priced from the matchup's pools when it is
+// recorded, so a client cannot store odds it was never offered.
 type CreateBetRequest struct {
 	UserAddress     string  `json:"user_address"`
 	MatchupID      string  `json:"matchup_id"`
-	PredictedWinner int     `json:"predicted_winner"`
-	BetAmount      int     `json:"bet_amount"`
-	Odds           float64 `json:"odds"`
+	PredictedWinner stores.Outcome `json:"predicted_winner"`
+	BetAmount      float64 `json:"bet_amount"`
 	TxnHash        string  `json:"txn_hash"`
 }
 
 type BetHandler struct {
-	BetStore stores.BetStore
+	BetStore    stores.BetStore
+	Verifier    *services.BetVerifier
+	Reconciler  *services.BetReconciler
+	Payouts     *services.PayoutService
+	Leaderboard stores.LeaderboardStore
+	Odds        *services.OddsService
 }
 
-func NewBetHandler(betStore stores.BetStore) *BetHandler {
+func NewBetHandler(betStore stores.BetStore, verifier *services.BetVerifier, reconciler *services.BetReconciler, payouts *services.PayoutService, leaderboard stores.LeaderboardStore, odds *services.OddsService) *BetHandler {
 	return &BetHandler{
-		BetStore: betStore,
+		BetStore:    betStore,
+		Verifier:    verifier,
+		Reconciler:  reconciler,
+		Payouts:     payouts,
+		Leaderboard: leaderboard,
+		Odds:        odds,
 	}
 }
 
+const (
+	defaultLeaderboardSize = 50
+	maxLeaderboardSize     = 500
+)
+
 
 func (bh *BetHandler) CreateBet(w http.ResponseWriter, r *http.Request) {
 	var bet CreateBetRequest
@@ -36,17 +56,48 @@ func (bh *BetHandler) CreateBet(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	if err := bet.PredictedWinner.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	newBet := &stores.Bet{
 		UserAddress:     bet.UserAddress,
 		MatchupID:      bet.MatchupID,
 		PredictedWinner: bet.PredictedWinner,
 		BetAmount:      bet.BetAmount,
-		Odds:           bet.Odds,
 		TxnHash:        bet.TxnHash,
 	}
 
+	err = bh.Verifier.Verify(r.Context(), newBet)
+	if errors.Is(err, services.ErrInvalidBet) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, services.ErrBettingClosed) {
+		http.Error(w, "Betting on this matchup has closed", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, services.ErrBetTransactionNotFound) {
+		http.Error(w, "Transaction not found, try again shortly", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to verify bet", http.StatusBadGateway)
+		return
+	}
+
+	err = bh.Odds.PriceBet(newBet)
+	if err != nil {
+		http.Error(w, "Failed to price bet", http.StatusInternalServerError)
+		return
+	}
+
 	err = bh.BetStore.CreateBet(newBet)
+	if errors.Is(err, stores.ErrDuplicateBet) {
+		bh.existingBet(w, newBet)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to create bet", http.StatusInternalServerError)
 		return
@@ -56,6 +107,32 @@ func (bh *BetHandler) CreateBet(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(newBet)
 }
 
+// existingBet answers a bet whose transaction is already recorded, by an earlier submission
+// or the event indexer. Resubmitting the same bet is a retry and gets the recorded bet back;
+// a different bet claiming the transaction is a conflict.
+func (bh *BetHandler) existingBet(w http.ResponseWriter, bet *stores.Bet) {
+	existing, err := bh.BetStore.GetBetByTxnHash(bet.TxnHash)
+	if err != nil {
+		http.Error(w, "Failed to create bet", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil || !sameBet(existing, bet) {
+		http.Error(w, stores.ErrDuplicateBet.Error(), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(existing)
+}
+
+// sameBet reports whether two bets are the same stake by the same bettor on the same outcome.
+func sameBet(a, b *stores.Bet) bool {
+	return strings.EqualFold(a.UserAddress, b.UserAddress) &&
+		a.PredictedWinner == b.PredictedWinner &&
+		a.AmountTinybars != nil && b.AmountTinybars != nil &&
+		*a.AmountTinybars == *b.AmountTinybars
+}
+
 func (bh *BetHandler) GetBetsByUserAddress(w http.ResponseWriter, r *http.Request) {
 	userAddress, err := utils.ReadIDParam(r, "address")
 	if err != nil {
@@ -72,6 +149,95 @@ func (bh *BetHandler) GetBetsByUserAddress(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(bets)
 }
 
+// GetPayouts reports each of a wallet's bets with its matchup's state, its payout, what is
+// still claimable and whether it was claimed.
+func (bh *BetHandler) GetPayouts(w http.ResponseWriter, r *http.Request) {
+	userAddress, err := utils.ReadIDParam(r, "address")
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid user address"})
+		return
+	}
+
+	payouts, err := bh.Payouts.GetPayouts(userAddress)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get payouts"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"payouts": payouts})
+}
+
+// GetStats returns a wallet's season record and its record in each gameweek.
+func (bh *BetHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	userAddress, err := utils.ReadIDParam(r, "address")
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid user address"})
+		return
+	}
+
+	season, err := bh.Leaderboard.GetBettorStats(userAddress)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get bettor stats"})
+		return
+	}
+	gameweeks, err := bh.Leaderboard.GetBettorGameweekStats(userAddress)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get bettor stats"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"season": season, "gameweeks": gameweeks})
+}
+
+// GetLeaderboard ranks bettors over the season, or one gameweek with ?gameweek=. ?sort= is
+// net (default), roi, staked, hit_rate or streak; ?min_bets= hides bettors with fewer settled
+// bets and ?limit= caps the rows.
+func (bh *BetHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := stores.LeaderboardFilter{Sort: stores.SortNet, Limit: defaultLeaderboardSize}
+
+	if s := query.Get("gameweek"); s != "" {
+		gameweek, err := strconv.Atoi(s)
+		if err != nil || gameweek < 1 {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid gameweek"})
+			return
+		}
+		filter.Gameweek = &gameweek
+	}
+	switch s := query.Get("sort"); s {
+	case "":
+	case stores.SortNet, stores.SortROI, stores.SortStaked, stores.SortHitRate, stores.SortStreak:
+		filter.Sort = s
+	default:
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "sort must be net, roi, staked, hit_rate or streak"})
+		return
+	}
+	if s := query.Get("min_bets"); s != "" {
+		minBets, err := strconv.Atoi(s)
+		if err != nil || minBets < 0 {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid min_bets"})
+			return
+		}
+		filter.MinBets = minBets
+	}
+	if s := query.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid limit"})
+			return
+		}
+		filter.Limit = min(limit, maxLeaderboardSize)
+	}
+
+	leaderboard, err := bh.Leaderboard.GetLeaderboard(filter)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get leaderboard"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"leaderboard": leaderboard, "game_week": filter.Gameweek, "sort": filter.Sort})
+}
+
 func (bh *BetHandler) GetNumberOfBets(w http.ResponseWriter, r *http.Request) {
 
 	matchup, err := utils.ReadIDParam(r, "matchup")
@@ -100,4 +266,41 @@ func (bh *BetHandler) GetNumberOfBets(w http.ResponseWriter, r *http.Request) {
 		"draw_bets": count.DrawBets,
 	}
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+// GetMarket summarises the betting on a matchup: stakes, bettors, largest bet and average
+// quoted odds per outcome, the odds its pools imply now and the pools' growth by hour.
+func (bh *BetHandler) GetMarket(w http.ResponseWriter, r *http.Request) {
+	matchup, err := utils.ReadIDParam(r, "matchup")
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "missing matchup parameter"})
+		return
+	}
+
+	market, err := bh.Odds.GetMarket(matchup)
+	if err == nil && market == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "matchup not found"})
+		return
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get market summary"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"market": market})
+}
+
+// ReconcileOutcomes runs one pass of checking stored bet outcomes against BetPlaced events.
+func (bh *BetHandler) ReconcileOutcomes(w http.ResponseWriter, r *http.Request) {
+	result, err := bh.Reconciler.ReconcileOutcomes(r.Context(), 500)
+	if err != nil && result == nil {
+		http.Error(w, "Failed to reconcile bets", http.StatusInternalServerError)
+		return
+	}
+
+	response := utils.Envelope{"result": result}
+	if err != nil {
+		response["error"] = err.Error()
+	}
+	utils.WriteJSON(w, http.StatusOK, response)
+}
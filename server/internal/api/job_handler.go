@@ -0,0 +1,48 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/divin3circle/fplduel/server/internal/jobs"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+	"github.com/divin3circle/fplduel/server/internal/utils"
+)
+
+type JobHandler struct {
+	Logger      *log.Logger
+	Scheduler   *jobs.Scheduler
+	JobRunStore stores.JobRunStore
+}
+
+func NewJobHandler(logger *log.Logger, scheduler *jobs.Scheduler, jobRunStore stores.JobRunStore) *JobHandler {
+	return &JobHandler{
+		Logger:      logger,
+		Scheduler:   scheduler,
+		JobRunStore: jobRunStore,
+	}
+}
+
+// HandleListJobRuns returns the registered jobs and their run history.
+// Optional query params: job (filter by name) and limit (default 50).
+func (jh *JobHandler) HandleListJobRuns(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := jh.JobRunStore.ListJobRuns(r.URL.Query().Get("job"), limit)
+	if err != nil {
+		jh.Logger.Printf("Error listing job runs: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Could not list job runs"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"jobs": jh.Scheduler.Jobs(), "runs": runs})
+}
@@ -2,20 +2,41 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+	"github.com/divin3circle/fplduel/server/internal/scoring"
+	"github.com/divin3circle/fplduel/server/internal/services"
 	"github.com/divin3circle/fplduel/server/internal/stores"
 	"github.com/divin3circle/fplduel/server/internal/utils"
 	hiero "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
 )
 
 type MatchupHandler struct {
-	Logger       *log.Logger
-	Client       *hiero.Client
-	MatchupStore stores.MatchupStore
+	Logger         *log.Logger
+	Client         *hiero.Client
+	FPL            *fpl.Client
+	Scorer         *scoring.Engine
+	MatchupService *services.MatchupService
+	Settlement     *services.SettlementService
+	Odds           *services.OddsService
+	Refunds        *services.RefundService
+	MatchupStore   stores.MatchupStore
+	RefundStore    stores.RefundStore
+}
+
+type SettleMatchupRequest struct {
+	Winner *stores.Outcome `json:"winner"`
+}
+
+type VoidMatchupRequest struct {
+	Reason string `json:"reason"`
 }
 
 type UpdateScoresRequest struct {
@@ -23,11 +44,18 @@ type UpdateScoresRequest struct {
 	HomeScore int `json:"home_score"`
 }
 
-func NewMatchupHandler(logger *log.Logger, client *hiero.Client, matchupStore stores.MatchupStore) *MatchupHandler {
+func NewMatchupHandler(logger *log.Logger, client *hiero.Client, fplClient *fpl.Client, scorer *scoring.Engine, matchupService *services.MatchupService, settlement *services.SettlementService, odds *services.OddsService, refunds *services.RefundService, matchupStore stores.MatchupStore, refundStore stores.RefundStore) *MatchupHandler {
 	return &MatchupHandler{
-		Logger:       logger,
-		Client:       client,
-		MatchupStore: matchupStore,
+		Logger:         logger,
+		Client:         client,
+		FPL:            fplClient,
+		Scorer:         scorer,
+		MatchupService: matchupService,
+		Settlement:     settlement,
+		Odds:           odds,
+		Refunds:        refunds,
+		MatchupStore:   matchupStore,
+		RefundStore:    refundStore,
 	}
 }
 
@@ -54,6 +82,70 @@ func (mh *MatchupHandler) GetMatchupByID(w http.ResponseWriter, r *http.Request)
 	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"matchup": matchup})
 }
 
+// GetMatchupTransitions lists a matchup's lifecycle transitions, oldest first.
+func (mh *MatchupHandler) GetMatchupTransitions(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		mh.Logger.Println("Error reading ID param:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "id is required"})
+		return
+	}
+
+	matchup, err := mh.MatchupStore.GetMatchupByID(id)
+	if err == nil && matchup == nil {
+		mh.Logger.Println("Matchup not found for ID:", id)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "matchup not found"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error getting matchup by ID:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup by ID"})
+		return
+	}
+
+	transitions, err := mh.MatchupStore.ListTransitions(id)
+	if err != nil {
+		mh.Logger.Println("Error listing matchup transitions:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup transitions"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"status": matchup.Status, "transitions": transitions})
+}
+
+// GetMatchupOdds prices a matchup's outcomes as its contract's getOdds would. An optional
+// stake query parameter, in HBAR, adds the odds and projected payout for that stake.
+func (mh *MatchupHandler) GetMatchupOdds(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		mh.Logger.Println("Error reading ID param:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "id is required"})
+		return
+	}
+
+	var stake int64
+	if s := r.URL.Query().Get("stake"); s != "" {
+		stake, err = contracts.ParseHbar(s)
+		if err != nil || stake <= 0 {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "stake must be a positive HBAR amount"})
+			return
+		}
+	}
+
+	odds, err := mh.Odds.GetOdds(id, stake)
+	if err == nil && odds == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "matchup not found"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error getting matchup odds:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup odds"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"odds": odds})
+}
+
 func (mh *MatchupHandler) GetAllMatchups(w http.ResponseWriter, r *http.Request) {
 	matchups, err := mh.MatchupStore.ListMatchups()
 	if err != nil {
@@ -89,33 +181,276 @@ func (mh *MatchupHandler) GetMatchupsByGameWeek(w http.ResponseWriter, r *http.R
 	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"matchups": matchups})
 }
 
+// CreateMatchups creates the upcoming gameweek's slate. Repeat calls return the existing
+// slate with 409 Conflict instead of generating a second one. The optional body picks the
+// slate size, team source and pairing strategy, e.g.
+// {"size": 20, "source": "league", "league_id": 314, "strategy": "seeded", "seed": 42}.
 func (mh *MatchupHandler) CreateMatchups(w http.ResponseWriter, r *http.Request) {
-	matchups, err := utils.GetMatchups(http.DefaultClient)
+	opts, err := readSlateOptions(r)
+	if errors.Is(err, services.ErrInvalidSlateOptions) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error decoding slate options:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid request body"})
+		return
+	}
 
+	slate, err := mh.MatchupService.CreateSlate(r.Context(), opts)
+	if errors.Is(err, services.ErrInvalidSlateOptions) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrBettingClosed) {
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, stores.ErrSlateExists) {
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": fmt.Sprintf("gameweek %d already has matchups", slate.Gameweek), "slate": slate})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error creating slate:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to create matchups"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"slate": slate, "message": fmt.Sprintf("created %d matchups for gameweek %d", len(slate.Matchups), slate.Gameweek)})
+}
+
+// RegenerateSlate retires the upcoming gameweek's slate and replaces it with a new one.
+// A slate whose matchups have confirmed bets is only replaced with ?force=true, which
+// voids those matchups and refunds their bettors; without it the request gets 409 Conflict.
+func (mh *MatchupHandler) RegenerateSlate(w http.ResponseWriter, r *http.Request) {
+	opts, err := readSlateOptions(r)
+	if errors.Is(err, services.ErrInvalidSlateOptions) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error decoding slate options:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid request body"})
+		return
+	}
+
+	force := r.URL.Query().Get("force") == "true"
+	slate, err := mh.MatchupService.RegenerateSlate(r.Context(), opts, force)
+	if errors.Is(err, services.ErrInvalidSlateOptions) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrBettingClosed) {
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, stores.ErrSlateHasBets) {
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": "matchups on the active slate have confirmed bets; retry with ?force=true to void them and refund their bettors"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error regenerating slate:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to regenerate matchups"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"slate": slate, "message": fmt.Sprintf("regenerated %d matchups for gameweek %d", len(slate.Matchups), slate.Gameweek)})
+}
+
+// RetryDeployment requeues a matchup whose contract deployment has failed.
+func (mh *MatchupHandler) RetryDeployment(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
 	if err != nil {
-		mh.Logger.Println("Error getting matchups:", err)
-		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchups"})
+		mh.Logger.Println("Error reading ID param:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "id is required"})
 		return
 	}
-	var createdMatchups []*stores.Matchup
-	failedToCreate := 0
 
-	for idx, matchup := range matchups {
-		err := mh.MatchupStore.CreateMatchup(matchup)
+	matchup, err := mh.MatchupStore.RetryDeployment(id)
+	if err != nil {
+		mh.Logger.Println("Error retrying deployment:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to retry deployment"})
+		return
+	}
+	if matchup == nil {
+		existing, err := mh.MatchupStore.GetMatchupByID(id)
+		if err == nil && existing == nil {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "matchup not found"})
+			return
+		}
 		if err != nil {
-			mh.Logger.Printf("Error creating matchup at index: %d, %v, %v", idx, matchup, err)
-			failedToCreate++
-			continue
+			mh.Logger.Println("Error getting matchup by ID:", err)
+			utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup by ID"})
+			return
 		}
-		createdMatchups = append(createdMatchups, matchups[idx])
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": fmt.Sprintf("deployment is %s, only failed deployments can be retried", existing.DeploymentStatus), "matchup": existing})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, utils.Envelope{"matchup": matchup})
+}
+
+// RetryFailedDeployments requeues every failed contract deployment.
+func (mh *MatchupHandler) RetryFailedDeployments(w http.ResponseWriter, r *http.Request) {
+	count, err := mh.MatchupStore.RetryFailedDeployments()
+	if err != nil {
+		mh.Logger.Println("Error retrying failed deployments:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to retry deployments"})
+		return
 	}
-	mh.Logger.Printf("%d/5 matchups failed to be created\n", failedToCreate)
 
-	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"matchups": matchups, "message": fmt.Sprintf("created %d matchups of 5", len(createdMatchups))})
+	utils.WriteJSON(w, http.StatusAccepted, utils.Envelope{"message": fmt.Sprintf("requeued %d deployments", count)})
+}
+
+// SettleMatchup settles a matchup's contract on chain. The winner follows from the stored scores
+// unless the body overrides it, e.g. {"winner": 1} for a draw.
+func (mh *MatchupHandler) SettleMatchup(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		mh.Logger.Println("Error reading ID param:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "id is required"})
+		return
+	}
+
+	var req SettleMatchupRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		mh.Logger.Println("Error decoding settle request:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid request body"})
+		return
+	}
+	if req.Winner != nil && !req.Winner.Valid() {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": stores.ErrInvalidOutcome.Error()})
+		return
+	}
+
+	matchup, err := mh.MatchupStore.GetMatchupByID(id)
+	if err == nil && matchup == nil {
+		mh.Logger.Println("Matchup not found for ID:", id)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "matchup not found"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error getting matchup by ID:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup by ID"})
+		return
+	}
+
+	err = mh.Settlement.SettleMatchup(r.Context(), matchup, req.Winner)
+	if errors.Is(err, services.ErrAlreadySettled) || errors.Is(err, services.ErrNotDeployed) ||
+		errors.Is(err, services.ErrMatchupVoided) || errors.Is(err, services.ErrBettingOpen) ||
+		errors.Is(err, stores.ErrInvalidTransition) {
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error(), "matchup": matchup})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error settling matchup:", err)
+		utils.WriteJSON(w, http.StatusBadGateway, utils.Envelope{"error": "failed to settle matchup contract"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"matchup": matchup})
+}
+
+// VoidMatchup cancels an unsettled matchup, e.g. {"reason": "gameweek postponed"}, voids its
+// contract and queues a refund for every bettor. If the contract call fails the matchup is
+// still voided and 202 Accepted is returned; the refund job retries the contract.
+func (mh *MatchupHandler) VoidMatchup(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		mh.Logger.Println("Error reading ID param:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "id is required"})
+		return
+	}
+
+	var req VoidMatchupRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		mh.Logger.Println("Error decoding void request:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid request body"})
+		return
+	}
+
+	matchup, err := mh.MatchupStore.GetMatchupByID(id)
+	if err == nil && matchup == nil {
+		mh.Logger.Println("Matchup not found for ID:", id)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "matchup not found"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error getting matchup by ID:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup by ID"})
+		return
+	}
+
+	err = mh.Refunds.VoidMatchup(r.Context(), matchup, req.Reason)
+	if errors.Is(err, services.ErrVoidReasonRequired) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrAlreadySettled) || errors.Is(err, services.ErrMatchupVoided) ||
+		errors.Is(err, stores.ErrInvalidTransition) {
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error(), "matchup": matchup})
+		return
+	}
+	if err != nil && matchup.Status != stores.MatchupVoided {
+		mh.Logger.Println("Error voiding matchup:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to void matchup"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error voiding matchup contract:", err)
+		utils.WriteJSON(w, http.StatusAccepted, utils.Envelope{"matchup": matchup, "error": "matchup voided, voiding its contract will be retried"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"matchup": matchup})
+}
+
+// GetMatchupRefunds lists the refunds of a voided matchup with each bettor's refund status.
+func (mh *MatchupHandler) GetMatchupRefunds(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		mh.Logger.Println("Error reading ID param:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "id is required"})
+		return
+	}
+
+	matchup, err := mh.MatchupStore.GetMatchupByID(id)
+	if err == nil && matchup == nil {
+		mh.Logger.Println("Matchup not found for ID:", id)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "matchup not found"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Error getting matchup by ID:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup by ID"})
+		return
+	}
+
+	refunds, err := mh.RefundStore.GetMatchupRefunds(id)
+	if err != nil {
+		mh.Logger.Println("Error getting matchup refunds:", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get matchup refunds"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"status": matchup.Status, "void_reason": matchup.VoidReason, "refunds": refunds})
+}
+
+// readSlateOptions decodes the optional slate options body. Malformed JSON is returned as
+// is; options that decode but can never produce a slate wrap services.ErrInvalidSlateOptions.
+func readSlateOptions(r *http.Request) (services.SlateOptions, error) {
+	var opts services.SlateOptions
+	err := json.NewDecoder(r.Body).Decode(&opts)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return opts, err
+	}
+	return opts, opts.Validate()
 }
 
 func (mh *MatchupHandler) GetCurrentGameweek(w http.ResponseWriter, r *http.Request) {
-	gw, err := utils.GetCurrentGameweek()
+	gw, err := utils.GetCurrentGameweek(r.Context(), mh.FPL)
 	if err != nil {
 		mh.Logger.Println("Error getting current gameweek:", err)
 		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to get current gameweek"})
@@ -153,6 +488,10 @@ func (mh *MatchupHandler) UpdateMatchupScores(w http.ResponseWriter, r *http.Req
 	}
 
 	err = mh.MatchupStore.UpdateMatchup(req.HomeScore, req.AwayScore, matchup)
+	if errors.Is(err, stores.ErrMatchupNotScorable) {
+		utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": fmt.Sprintf("matchup is %s, only locked, live or final matchups can be scored", matchup.Status)})
+		return
+	}
 	if err != nil {
 		mh.Logger.Println("Error updating matchup scores:", err)
 		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "failed to update matchup scores"})
@@ -161,3 +500,39 @@ func (mh *MatchupHandler) UpdateMatchupScores(w http.ResponseWriter, r *http.Req
 
 	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"message": "matchup scores updated successfully"})
 }
+
+// ScoreGameweek scores every matchup of a gameweek. If only some matchups fail it answers
+// 207 Multi-Status with the scored matchups and an error for each failed one.
+func (mh *MatchupHandler) ScoreGameweek(w http.ResponseWriter, r *http.Request) {
+	gameweekStr, err := utils.ReadIDParam(r, "gameweek")
+	if err != nil {
+		mh.Logger.Println("Error reading gameweek param:", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "gameweek is required for this resource"})
+		return
+	}
+
+	gameweek, err := strconv.Atoi(gameweekStr)
+	if err != nil {
+		mh.Logger.Println("Error converting gameweek to int", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "invalid gameweek"})
+		return
+	}
+
+	matchups, err := mh.Scorer.ScoreGameweek(r.Context(), gameweek)
+	if err != nil && matchups == nil {
+		mh.Logger.Println("Error scoring gameweek:", err)
+		utils.WriteJSON(w, http.StatusBadGateway, utils.Envelope{"error": "failed to score gameweek"})
+		return
+	}
+	if err != nil {
+		mh.Logger.Println("Some matchups failed to score:", err)
+		failures := []utils.Envelope{}
+		for _, matchupErr := range scoring.MatchupErrors(err) {
+			failures = append(failures, utils.Envelope{"matchup_id": matchupErr.MatchupID, "error": matchupErr.Err.Error()})
+		}
+		utils.WriteJSON(w, http.StatusMultiStatus, utils.Envelope{"matchups": matchups, "errors": failures})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"matchups": matchups})
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/divin3circle/fplduel/server/internal/fpl"
 	"github.com/divin3circle/fplduel/server/internal/stores"
 	"github.com/divin3circle/fplduel/server/internal/utils"
 	hiero "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
@@ -14,13 +15,15 @@ import (
 type PlayerHandler struct {
 	Logger    *log.Logger
 	Client    *hiero.Client
+	FPL       *fpl.Client
 	PlayerStore stores.PlayerStore
 }
 
-func NewPlayerHandler(logger *log.Logger, client *hiero.Client, playerStore stores.PlayerStore) *PlayerHandler {
+func NewPlayerHandler(logger *log.Logger, client *hiero.Client, fplClient *fpl.Client, playerStore stores.PlayerStore) *PlayerHandler {
 	return &PlayerHandler{
 		Logger:    logger,
 		Client:    client,
+		FPL:       fplClient,
 		PlayerStore: playerStore,
 	}
 }
@@ -115,7 +118,7 @@ func (ph *PlayerHandler) HandleGetPlayerByCode(w http.ResponseWriter, r *http.Re
 }
 
 func (ph *PlayerHandler) HandleUpdatePlayers(w http.ResponseWriter, r *http.Request){
-	players , err := utils.GetAllPlayers(http.DefaultClient)
+	players , err := utils.GetAllPlayers(r.Context(), ph.FPL)
 	start := time.Now()
 	ph.Logger.Printf("Fetched %d players from FPL API in %v", len(players), time.Since(start))
 	if err != nil {
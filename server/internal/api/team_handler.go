@@ -2,12 +2,12 @@ package api
 
 import (
 	"encoding/json"
-	"io"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
+	"github.com/divin3circle/fplduel/server/internal/fpl"
 	"github.com/divin3circle/fplduel/server/internal/stores"
 	"github.com/divin3circle/fplduel/server/internal/utils"
 	hiero "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
@@ -16,6 +16,7 @@ import (
 type TeamHandler struct {
 	Logger    *log.Logger
 	Client    *hiero.Client
+	FPL       *fpl.Client
 	TeamStore stores.TeamStore
 }
 
@@ -23,10 +24,11 @@ type TeamJerseyRequest struct {
 	Position int `json:"position"`
 }
 
-func NewTeamHandler(logger *log.Logger, client *hiero.Client, teamStore stores.TeamStore) *TeamHandler {
+func NewTeamHandler(logger *log.Logger, client *hiero.Client, fplClient *fpl.Client, teamStore stores.TeamStore) *TeamHandler {
 	return &TeamHandler{
 		Logger:    logger,
 		Client:    client,
+		FPL:       fplClient,
 		TeamStore: teamStore,
 	}
 }
@@ -110,29 +112,12 @@ func (th *TeamHandler) HandleCreateOrUpdateTeams(w http.ResponseWriter, r *http.
 		return
 	}
 
-	data, err := utils.GetBootstrapData(http.DefaultClient)
+	teams, err := utils.GetAllTeams(r.Context(), th.FPL)
 	if err != nil {
 		th.Logger.Printf("Error reading bootstrap data: %v", err)
 		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Could not read bootstrap data"})
 		return
 	}
-	if data == nil || data.Teams == nil {
-		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "no teams in bootstrap data"})
-		return
-	}
-
-	var teams []*stores.Team
-	now := time.Now().UTC()
-	for _, t := range *data.Teams {
-		teams = append(teams, &stores.Team{
-			ID:        t.ID,
-			Code:      t.Code,
-			Name:      t.Name,
-			ShortName: t.ShortName,
-			Strength:  t.Strength,
-			UpdatedAt: now,
-		})
-	}
 
 	if err := th.TeamStore.UpdateTeams(teams); err != nil {
 		th.Logger.Printf("Error updating teams: %v", err)
@@ -185,33 +170,35 @@ func (th *TeamHandler) HandleGetManagerPicks(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	httpClient := &http.Client{Timeout: 10 * time.Second}
-
-	url := "https://fantasy.premierleague.com/api/entry/" + managerIdStr + "/event/" + gameWeek + "/picks/"
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	managerId, err := strconv.Atoi(managerIdStr)
 	if err != nil {
-		th.Logger.Printf("Error creating upstream request: %v", err)
-		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Upstream request init failed"})
+		th.Logger.Printf("Error converting manager ID to int: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid manager ID"})
 		return
 	}
-	req.Header.Set("User-Agent", "fplduel/1.0 (+https://github.com/divin3circle/fplduel)")
 
-	resp, err := httpClient.Do(req)
+	gameweek, err := strconv.Atoi(gameWeek)
 	if err != nil {
-		th.Logger.Printf("Error calling upstream: %v", err)
-		utils.WriteJSON(w, http.StatusBadGateway, utils.Envelope{"error": "Upstream call failed"})
+		th.Logger.Printf("Error converting gameweek to int: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid gameweek"})
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		th.Logger.Printf("Upstream returned status %d", resp.StatusCode)
+	body, err := th.FPL.GetEntryPicksRaw(r.Context(), managerId, gameweek)
+	var statusErr *fpl.StatusError
+	if errors.As(err, &statusErr) {
+		th.Logger.Printf("Upstream returned status %d", statusErr.StatusCode)
 		utils.WriteJSON(w, http.StatusBadGateway, utils.Envelope{"error": "Upstream returned non-200"})
 		return
 	}
+	if err != nil {
+		th.Logger.Printf("Error calling upstream: %v", err)
+		utils.WriteJSON(w, http.StatusBadGateway, utils.Envelope{"error": "Upstream call failed"})
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if _, err := io.Copy(w, resp.Body); err != nil {
-		th.Logger.Printf("Error streaming upstream body: %v", err)
+	if _, err := w.Write(body); err != nil {
+		th.Logger.Printf("Error writing upstream body: %v", err)
 	}
 }
@@ -6,8 +6,15 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/divin3circle/fplduel/server/internal/api"
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+	"github.com/divin3circle/fplduel/server/internal/jobs"
+	"github.com/divin3circle/fplduel/server/internal/mirror"
+	"github.com/divin3circle/fplduel/server/internal/scoring"
+	"github.com/divin3circle/fplduel/server/internal/services"
 	"github.com/divin3circle/fplduel/server/internal/stores"
 	"github.com/divin3circle/fplduel/server/migrations"
 	hiero "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
@@ -18,32 +25,34 @@ type Application struct {
 	Logger         *log.Logger
 	DB             *sql.DB
 	Hiero          *hiero.Client
+	Scheduler      *jobs.Scheduler
 	MatchupHandler *api.MatchupHandler
 	TeamHandler    *api.TeamHandler
 	PlayerHandler  *api.PlayerHandler
 	BetHandler    *api.BetHandler
+	JobHandler     *api.JobHandler
 }
 
-func loadEnvironmentVariables() {
+func loadEnvironmentVariables(logger *log.Logger) {
 	err := godotenv.Load()
 
 	if err != nil {
 		panic("Error loading .env file")
 	}
 
-	fmt.Println("Environment variables loaded successfully")
+	logger.Println("Environment variables loaded successfully")
 }
 
-func createHieroClient() (*hiero.Client, error) {
+func createHieroClient(logger *log.Logger) (*hiero.Client, error) {
 	accountID, err := hiero.AccountIDFromString(os.Getenv("OPERATOR_ACCOUNT_ID"))
 	if err != nil {
-		log.Println("Error getting account ID from environment variable:", err)
+		logger.Println("Error getting account ID from environment variable:", err)
 		return nil, err
 	}
 
 	privateKey, err := hiero.PrivateKeyFromStringEd25519(os.Getenv("OPERATOR_KEY"))
 	if err != nil {
-		log.Println("Error getting private key from environment variable:", err)
+		logger.Println("Error getting private key from environment variable:", err)
 		return nil, err
 	}
 
@@ -53,10 +62,103 @@ func createHieroClient() (*hiero.Client, error) {
 	return client, nil
 }
 
+// createFPLClient builds the FPL API client used by every handler, with its response cache attached.
+func createFPLClient(logger *log.Logger, db *sql.DB) (*fpl.Client, error) {
+	client, err := newFPLClientForMode(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// FPL_CACHE selects where responses are cached: memory (default), postgres or none
+	switch os.Getenv("FPL_CACHE") {
+	case "", "memory":
+		client.Cache = fpl.NewMemoryCache()
+	case "postgres":
+		client.Cache = fpl.NewTieredCache(fpl.NewMemoryCache(), stores.NewPostgresFPLCacheStore(db))
+	case "none":
+	default:
+		return nil, fmt.Errorf("unknown FPL_CACHE %q", os.Getenv("FPL_CACHE"))
+	}
+	return client, nil
+}
+
+// newFPLClientForMode honours FPL_MODE: record saves every upstream response to FPL_FIXTURES_DIR,
+// replay serves those fixtures from a local server so nothing hits the network. The directory
+// defaults to testdata/fpl, the fixtures checked in next to go.mod.
+func newFPLClientForMode(logger *log.Logger) (*fpl.Client, error) {
+	baseURL := os.Getenv("FPL_BASE_URL")
+	fixturesDir := os.Getenv("FPL_FIXTURES_DIR")
+	if fixturesDir == "" {
+		fixturesDir = "testdata/fpl"
+	}
+
+	switch os.Getenv("FPL_MODE") {
+	case "record":
+		client := fpl.NewClient(baseURL, nil)
+		recorder, err := fpl.NewRecorder(fixturesDir, client.BaseURL, client.HTTPClient.Transport)
+		if err != nil {
+			return nil, err
+		}
+		client.HTTPClient.Transport = recorder
+		logger.Println("Recording FPL responses to", fixturesDir)
+		return client, nil
+	case "replay":
+		if _, err := os.Stat(fixturesDir); err != nil {
+			return nil, fmt.Errorf("FPL_MODE=replay needs recorded fixtures: %w", err)
+		}
+		server := fpl.NewReplayServer(fixturesDir)
+		logger.Println("Replaying FPL fixtures from", fixturesDir, "on", server.URL)
+		return fpl.NewClient(server.URL, nil), nil
+	case "":
+		return fpl.NewClient(baseURL, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown FPL_MODE %q", os.Getenv("FPL_MODE"))
+	}
+}
+
+// createContractDeployer picks how matchup contracts are deployed. CONTRACT_DEPLOYER is hiero
+// (default, deploys with the operator account), node (posts to the contracts/backend server
+// at CONTRACT_SERVER_URL) or fake (in-memory addresses, nothing touches the network).
+func createContractDeployer(logger *log.Logger, client *hiero.Client, mirrorClient *mirror.Client) (contracts.ContractDeployer, error) {
+	switch os.Getenv("CONTRACT_DEPLOYER") {
+	case "", contracts.DeployerHiero:
+		return contracts.NewHieroDeployer(client, mirrorClient, os.Getenv("CONTRACT_ARTIFACT_PATH")), nil
+	case contracts.DeployerNode:
+		return contracts.NewNodeDeployer(os.Getenv("CONTRACT_SERVER_URL")), nil
+	case contracts.DeployerFake:
+		logger.Println("Using fake contract deployer, matchup contracts will not exist on chain")
+		return contracts.NewFakeDeployer(), nil
+	default:
+		return nil, fmt.Errorf("unknown CONTRACT_DEPLOYER %q", os.Getenv("CONTRACT_DEPLOYER"))
+	}
+}
+
+// createContractSettler settles with the operator account, which deploys the contracts and so
+// owns them. With the node deployer the contract server's wallet must be that same account.
+// The fake deployer's contracts can only be settled by the fake settler.
+func createContractSettler(client *hiero.Client) contracts.ContractSettler {
+	if os.Getenv("CONTRACT_DEPLOYER") == contracts.DeployerFake {
+		return contracts.NewFakeSettler()
+	}
+	return contracts.NewHieroSettler(client)
+}
+
+// bettingEndOffset reads BETTING_END_OFFSET, a duration such as "-30m" added to each
+// gameweek deadline to get the close of betting. Unset, betting closes at the deadline.
+func bettingEndOffset() (time.Duration, error) {
+	offset := os.Getenv("BETTING_END_OFFSET")
+	if offset == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(offset)
+}
+
 func NewApplication() (*Application, error) {
-	loadEnvironmentVariables()
+	logger := log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lshortfile)
 
-	client, err := createHieroClient()
+	loadEnvironmentVariables(logger)
+
+	client, err := createHieroClient(logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Hiero client: %w", err)
 	}
@@ -65,35 +167,87 @@ func NewApplication() (*Application, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
-	log.Println("Connected to database")
+	logger.Println("Connected to database")
 
 	err = stores.MigrateFS(db, migrations.FS, ".")
 	if err != nil {
 		panic(err)
 	}
 
-	logger := log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lshortfile)
+	fplClient, err := createFPLClient(logger, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FPL client: %w", err)
+	}
+
+	mirrorClient := mirror.NewClient(os.Getenv("MIRROR_NODE_URL"), nil)
+	deployer, err := createContractDeployer(logger, client, mirrorClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contract deployer: %w", err)
+	}
 
 	// STORES
 	matchupStore := stores.NewPostgresMatchupStore(db)
 	teamsStore := stores.NewPostgresTeamsStore(db)
 	playersStore := stores.NewPostgresPlayersStore(db)
 	betStore := stores.NewPostgresBetStore(db)
+	jobRunStore := stores.NewPostgresJobRunStore(db)
+	slateStore := stores.NewPostgresSlateStore(db)
+	contractEventStore := stores.NewPostgresContractEventStore(db)
+	refundStore := stores.NewPostgresRefundStore(db)
+	leaderboardStore := stores.NewPostgresLeaderboardStore(db)
+
+	// SERVICES
+	scorer := scoring.NewEngine(logger, fplClient, matchupStore, playersStore)
+	matchupService := services.NewMatchupService(logger, fplClient, deployer, matchupStore, slateStore)
+	matchupService.BettingEndOffset, err = bettingEndOffset()
+	if err != nil {
+		return nil, fmt.Errorf("invalid BETTING_END_OFFSET: %w", err)
+	}
+	betVerifier := services.NewBetVerifier(mirrorClient, matchupStore)
+	betReconciler := services.NewBetReconciler(logger, mirrorClient, betStore, matchupStore)
+	oddsService := services.NewOddsService(matchupStore, betStore)
+	payoutService := services.NewPayoutService(betStore)
+	eventIndexer := services.NewEventIndexer(logger, mirrorClient, matchupStore, contractEventStore)
+	settler := createContractSettler(client)
+	settlementService := services.NewSettlementService(logger, settler, matchupStore)
+	refundService := services.NewRefundService(logger, settler, matchupStore, refundStore)
+
+	// JOBS
+	scheduler := jobs.NewScheduler(logger, jobRunStore)
+	scheduler.Register(jobs.RefreshPlayers(fplClient, playersStore))
+	scheduler.Register(jobs.RefreshTeams(fplClient, teamsStore))
+	scheduler.Register(jobs.CreateMatchups(matchupService))
+	scheduler.Register(jobs.DeployContracts(matchupService))
+	scheduler.Register(jobs.LockMatchups(matchupService))
+	scheduler.Register(jobs.ProcessRefunds(refundService))
+	scheduler.Register(jobs.ReconcileBetOutcomes(betReconciler))
+	scheduler.Register(jobs.IndexContractEvents(eventIndexer))
+	scheduler.Register(jobs.LiveScores(fplClient, scorer))
+	scheduler.Register(jobs.SettleGameweek(fplClient, scorer, settlementService, jobRunStore))
+	// SCHEDULER_ENABLED=true runs the jobs in this process; only one instance should set it
+	if os.Getenv("SCHEDULER_ENABLED") == "true" {
+		scheduler.Start()
+	} else {
+		logger.Println("Scheduler disabled; set SCHEDULER_ENABLED=true to run jobs")
+	}
 
 	// HANDLERS
-	matchupHandler := api.NewMatchupHandler(logger, client, matchupStore)
-	teamHandler := api.NewTeamHandler(logger, client, teamsStore)
-	playerHandler := api.NewPlayerHandler(logger, client, playersStore)
-	betHandler := api.NewBetHandler(betStore)
+	matchupHandler := api.NewMatchupHandler(logger, client, fplClient, scorer, matchupService, settlementService, oddsService, refundService, matchupStore, refundStore)
+	teamHandler := api.NewTeamHandler(logger, client, fplClient, teamsStore)
+	playerHandler := api.NewPlayerHandler(logger, client, fplClient, playersStore)
+	betHandler := api.NewBetHandler(betStore, betVerifier, betReconciler, payoutService, leaderboardStore, oddsService)
+	jobHandler := api.NewJobHandler(logger, scheduler, jobRunStore)
 
 	return &Application{
 		Logger:         logger,
 		DB:             db,
 		Hiero:          client,
+		Scheduler:      scheduler,
 		MatchupHandler: matchupHandler,
 		TeamHandler:    teamHandler,
 		PlayerHandler:  playerHandler,
 		BetHandler: betHandler,
+		JobHandler:     jobHandler,
 	}, nil
 }
 
@@ -0,0 +1,35 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// BetSelector is the 4-byte selector of FPLMatchupBet.bet(uint8), hex-encoded with a 0x prefix.
+var BetSelector = functionSelector("bet(uint8)")
+
+func functionSelector(signature string) string {
+	return "0x" + hex.EncodeToString(keccak256(signature)[:4])
+}
+
+// DecodeBetCall returns the outcome a bet(uint8) call was made with, given its hex calldata.
+func DecodeBetCall(input string) (stores.Outcome, error) {
+	input = strings.ToLower(strings.TrimPrefix(input, "0x"))
+	selector := strings.TrimPrefix(BetSelector, "0x")
+	if !strings.HasPrefix(input, selector) {
+		return 0, fmt.Errorf("not a call to bet(uint8)")
+	}
+	words, err := decodeWords(strings.TrimPrefix(input, selector), 1)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bet(uint8) arguments: %w", err)
+	}
+
+	outcome, err := decodeOutcome(words[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid bet(uint8) outcome: %w", err)
+	}
+	return outcome, nil
+}
@@ -0,0 +1,102 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+const (
+	DeployerHiero = "hiero"
+	DeployerNode  = "node"
+)
+
+// WeiPerHbar is the scale FPLMatchupBet uses for pools and odds. The contract multiplies
+// incoming tinybars by 1e10 so HBAR amounts line up with 18-decimal wei.
+var WeiPerHbar = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// TinybarsPerHbar is the number of tinybars in one HBAR.
+const TinybarsPerHbar = stores.TinybarsPerHbar
+
+// WeiPerTinybar is the factor the contract scales msg.value by, and divides payouts by.
+var WeiPerTinybar = big.NewInt(stores.WeiPerTinybar)
+
+// Default virtual pools seeding the initial odds of every matchup, in HBAR.
+const (
+	DefaultVirtualPoolA    = 100
+	DefaultVirtualPoolDraw = 50
+	DefaultVirtualPoolB    = 100
+)
+
+// DeployParams are the FPLMatchupBet constructor arguments. Virtual pools are wei-scaled.
+//
+// OnSubmit, if set, is called with the contract create's transaction ID just before it is
+// submitted; an error stops the deployment. Deployers that cannot know the ID in advance
+// never call it.
+type DeployParams struct {
+	BettingEnd      time.Time
+	VirtualPoolA    *big.Int
+	VirtualPoolDraw *big.Int
+	VirtualPoolB    *big.Int
+	OnSubmit        func(transactionID string) error
+}
+
+// DefaultDeployParams returns the default virtual pools with betting closing at bettingEnd.
+func DefaultDeployParams(bettingEnd time.Time) DeployParams {
+	return DeployParams{
+		BettingEnd:      bettingEnd,
+		VirtualPoolA:    HbarToWei(DefaultVirtualPoolA),
+		VirtualPoolDraw: HbarToWei(DefaultVirtualPoolDraw),
+		VirtualPoolB:    HbarToWei(DefaultVirtualPoolB),
+	}
+}
+
+// Deployment describes a deployed FPLMatchupBet contract.
+type Deployment struct {
+	ContractAddress string `json:"contract_address"`
+	ContractID      string `json:"contract_id,omitempty"`
+	TransactionID   string `json:"transaction_id,omitempty"`
+	TransactionHash string `json:"transaction_hash"`
+}
+
+// ContractDeployer deploys one FPLMatchupBet contract per matchup. FindDeployment looks up
+// the contract created by a transaction passed to OnSubmit, so a deployment whose result was
+// lost can be recovered; it returns nil if that transaction created no contract.
+type ContractDeployer interface {
+	Deploy(ctx context.Context, params DeployParams) (*Deployment, error)
+	FindDeployment(ctx context.Context, transactionID string) (*Deployment, error)
+}
+
+func HbarToWei(hbar int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(hbar), WeiPerHbar)
+}
+
+// WeiToTinybars converts a wei-scaled contract amount back to the tinybars it represents,
+// rounding down as the contract does when paying out.
+func WeiToTinybars(wei *big.Int) int64 {
+	return new(big.Int).Quo(wei, WeiPerTinybar).Int64()
+}
+
+// ParseHbar parses a decimal HBAR amount, such as "2.5", into tinybars.
+func ParseHbar(s string) (int64, error) {
+	hbar, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid HBAR amount %q", s)
+	}
+	tinybars := hbar.Mul(hbar, new(big.Rat).SetInt64(TinybarsPerHbar))
+	if !tinybars.IsInt() || !tinybars.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid HBAR amount %q: must be whole tinybars", s)
+	}
+	return tinybars.Num().Int64(), nil
+}
+
+// FormatEther renders a wei amount as a decimal HBAR string, the format ethers.parseEther reads.
+func FormatEther(wei *big.Int) string {
+	s := new(big.Rat).SetFrac(wei, WeiPerHbar).FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
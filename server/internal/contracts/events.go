@@ -0,0 +1,159 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+	"golang.org/x/crypto/sha3"
+)
+
+// Event topics (keccak256 of the event signature) emitted by FPLMatchupBet.
+var (
+	BetPlacedTopic = eventTopic("BetPlaced(address,uint8,uint256)")
+	SettledTopic   = eventTopic("Settled(uint8)")
+	ClaimedTopic   = eventTopic("Claimed(address,uint256)")
+	VoidedTopic    = eventTopic("Voided()")
+	RefundedTopic  = eventTopic("Refunded(address,uint256)")
+)
+
+// BetPlacedEvent is a decoded BetPlaced log. Amount is wei-scaled, as the contract stores it.
+type BetPlacedEvent struct {
+	Bettor  string
+	Outcome stores.Outcome
+	Amount  *big.Int
+}
+
+// ClaimedEvent is a decoded Claimed log. Payout is wei-scaled; the claimer received
+// Payout / WeiPerTinybar tinybars.
+type ClaimedEvent struct {
+	Claimer string
+	Payout  *big.Int
+}
+
+// RefundedEvent is a decoded Refunded log. Amount is wei-scaled; the bettor received
+// Amount / WeiPerTinybar tinybars.
+type RefundedEvent struct {
+	Bettor string
+	Amount *big.Int
+}
+
+func eventTopic(signature string) string {
+	return "0x" + hex.EncodeToString(keccak256(signature))
+}
+
+func keccak256(s string) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(s))
+	return hash.Sum(nil)
+}
+
+// DecodeBetPlaced decodes a BetPlaced log from its hex topics and data.
+func DecodeBetPlaced(topics []string, data string) (*BetPlacedEvent, error) {
+	if len(topics) != 2 || !strings.EqualFold(topics[0], BetPlacedTopic) {
+		return nil, fmt.Errorf("not a BetPlaced log")
+	}
+	bettor, err := decodeWords(topics[1], 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BetPlaced bettor topic: %w", err)
+	}
+	words, err := decodeWords(data, 2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BetPlaced data: %w", err)
+	}
+
+	outcome, err := decodeOutcome(words[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid BetPlaced outcome: %w", err)
+	}
+	return &BetPlacedEvent{
+		Bettor:  decodeAddress(bettor[0]),
+		Outcome: outcome,
+		Amount:  new(big.Int).SetBytes(words[1]),
+	}, nil
+}
+
+// DecodeSettled returns the winner of a Settled log.
+func DecodeSettled(topics []string, data string) (stores.Outcome, error) {
+	if len(topics) != 1 || !strings.EqualFold(topics[0], SettledTopic) {
+		return 0, fmt.Errorf("not a Settled log")
+	}
+	words, err := decodeWords(data, 1)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Settled data: %w", err)
+	}
+	winner, err := decodeOutcome(words[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid Settled winner: %w", err)
+	}
+	return winner, nil
+}
+
+// DecodeClaimed decodes a Claimed log from its hex topics and data.
+func DecodeClaimed(topics []string, data string) (*ClaimedEvent, error) {
+	if len(topics) != 2 || !strings.EqualFold(topics[0], ClaimedTopic) {
+		return nil, fmt.Errorf("not a Claimed log")
+	}
+	claimer, err := decodeWords(topics[1], 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Claimed claimer topic: %w", err)
+	}
+	words, err := decodeWords(data, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Claimed data: %w", err)
+	}
+	return &ClaimedEvent{
+		Claimer: decodeAddress(claimer[0]),
+		Payout:  new(big.Int).SetBytes(words[0]),
+	}, nil
+}
+
+// DecodeRefunded decodes a Refunded log from its hex topics and data.
+func DecodeRefunded(topics []string, data string) (*RefundedEvent, error) {
+	if len(topics) != 2 || !strings.EqualFold(topics[0], RefundedTopic) {
+		return nil, fmt.Errorf("not a Refunded log")
+	}
+	bettor, err := decodeWords(topics[1], 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Refunded bettor topic: %w", err)
+	}
+	words, err := decodeWords(data, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Refunded data: %w", err)
+	}
+	return &RefundedEvent{
+		Bettor: decodeAddress(bettor[0]),
+		Amount: new(big.Int).SetBytes(words[0]),
+	}, nil
+}
+
+func decodeOutcome(word []byte) (stores.Outcome, error) {
+	outcome := new(big.Int).SetBytes(word)
+	if !outcome.IsInt64() || !stores.Outcome(outcome.Int64()).Valid() {
+		return 0, fmt.Errorf("%s is not an outcome", outcome)
+	}
+	return stores.Outcome(outcome.Int64()), nil
+}
+
+// decodeAddress returns the 20-byte address right-aligned in an ABI word.
+func decodeAddress(word []byte) string {
+	return "0x" + hex.EncodeToString(word[12:])
+}
+
+// decodeWords splits hex-encoded ABI data into n 32-byte words.
+func decodeWords(s string, n int) ([][]byte, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != 32*n {
+		return nil, fmt.Errorf("expected %d bytes, got %d", 32*n, len(raw))
+	}
+	words := make([][]byte, n)
+	for i := range words {
+		words[i] = raw[32*i : 32*(i+1)]
+	}
+	return words, nil
+}
@@ -0,0 +1,229 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+const DeployerFake = "fake"
+
+// ErrSimulatedFailure is returned by FakeDeployer for deployments it was told to fail.
+var ErrSimulatedFailure = errors.New("simulated deployment failure")
+
+// FakeDeployer is an in-memory ContractDeployer for tests and offline development. The n-th
+// deployment (1-based) gets address 0x000…n, contract ID 0.0.n and a matching fake hash, so
+// results are deterministic.
+type FakeDeployer struct {
+	mu          sync.Mutex
+	calls       []DeployParams
+	failNext    int
+	failOn      map[int]bool
+	loseNext    int
+	deployments map[string]*Deployment
+}
+
+func NewFakeDeployer() *FakeDeployer {
+	return &FakeDeployer{failOn: map[int]bool{}, deployments: map[string]*Deployment{}}
+}
+
+func (f *FakeDeployer) Deploy(ctx context.Context, params DeployParams) (*Deployment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, params)
+	n := len(f.calls)
+	if f.failNext > 0 {
+		f.failNext--
+		return nil, fmt.Errorf("deployment %d: %w", n, ErrSimulatedFailure)
+	}
+	if f.failOn[n] {
+		return nil, fmt.Errorf("deployment %d: %w", n, ErrSimulatedFailure)
+	}
+
+	deployment := &Deployment{
+		ContractAddress: fmt.Sprintf("0x%040x", n),
+		ContractID:      fmt.Sprintf("0.0.%d", n),
+		TransactionID:   fmt.Sprintf("0.0.2@%d.000000000", n),
+		TransactionHash: fmt.Sprintf("0x%096x", n),
+	}
+	if params.OnSubmit != nil {
+		if err := params.OnSubmit(deployment.TransactionID); err != nil {
+			return nil, err
+		}
+	}
+	f.deployments[deployment.TransactionID] = deployment
+	if f.loseNext > 0 {
+		f.loseNext--
+		return nil, fmt.Errorf("deployment %d: receipt lost: %w", n, ErrSimulatedFailure)
+	}
+	return deployment, nil
+}
+
+// FindDeployment returns the contract created by a transaction, including ones whose result
+// was lost.
+func (f *FakeDeployer) FindDeployment(ctx context.Context, transactionID string) (*Deployment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.deployments[transactionID], nil
+}
+
+// FailNext makes the next n deployments fail.
+func (f *FakeDeployer) FailNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failNext = n
+}
+
+// LoseNext makes the next n deployments create their contract but return an error, as when
+// the receipt never arrives.
+func (f *FakeDeployer) LoseNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loseNext = n
+}
+
+// FailOn makes the given deployments (1-based call numbers) fail.
+func (f *FakeDeployer) FailOn(calls ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, n := range calls {
+		f.failOn[n] = true
+	}
+}
+
+// Calls returns the parameters of every deployment attempted so far, including failed ones.
+func (f *FakeDeployer) Calls() []DeployParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]DeployParams(nil), f.calls...)
+}
+
+// FakeSettler is an in-memory ContractSettler. Like the contract, it refuses to settle or
+// void the same address twice, to settle a voided one, or to refund a bettor twice.
+type FakeSettler struct {
+	mu       sync.Mutex
+	settled  map[string]stores.Outcome
+	voided   map[string]bool
+	refunded map[string]bool
+	failing  map[string]bool
+}
+
+func NewFakeSettler() *FakeSettler {
+	return &FakeSettler{
+		settled:  map[string]stores.Outcome{},
+		voided:   map[string]bool{},
+		refunded: map[string]bool{},
+		failing:  map[string]bool{},
+	}
+}
+
+func (f *FakeSettler) Settle(ctx context.Context, contractAddress string, winner stores.Outcome) (*Settlement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failing[contractAddress] {
+		return nil, fmt.Errorf("settle %s: %w", contractAddress, ErrSimulatedFailure)
+	}
+	if _, ok := f.settled[contractAddress]; ok {
+		return nil, fmt.Errorf("settle %s: already settled", contractAddress)
+	}
+	if f.voided[contractAddress] {
+		return nil, fmt.Errorf("settle %s: voided", contractAddress)
+	}
+	f.settled[contractAddress] = winner
+
+	n := len(f.settled)
+	return &Settlement{
+		Winner:          winner,
+		TransactionID:   fmt.Sprintf("0.0.2@%d.000000001", n),
+		TransactionHash: fmt.Sprintf("0x%096x", 1<<32+n),
+		SettledAt:       time.Now().UTC(),
+	}, nil
+}
+
+func (f *FakeSettler) Void(ctx context.Context, contractAddress string) (*Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failing[contractAddress] {
+		return nil, fmt.Errorf("void %s: %w", contractAddress, ErrSimulatedFailure)
+	}
+	if _, ok := f.settled[contractAddress]; ok {
+		return nil, fmt.Errorf("void %s: already settled", contractAddress)
+	}
+	if f.voided[contractAddress] {
+		return nil, fmt.Errorf("void %s: already voided", contractAddress)
+	}
+	f.voided[contractAddress] = true
+
+	n := len(f.voided)
+	return &Transaction{
+		TransactionID:   fmt.Sprintf("0.0.2@%d.000000002", n),
+		TransactionHash: fmt.Sprintf("0x%096x", 2<<32+n),
+	}, nil
+}
+
+// Refund refunds any bettor of a voided contract once; the fake does not track stakes.
+func (f *FakeSettler) Refund(ctx context.Context, contractAddress, bettor string) (*Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failing[contractAddress] {
+		return nil, fmt.Errorf("refund %s: %w", contractAddress, ErrSimulatedFailure)
+	}
+	if !f.voided[contractAddress] {
+		return nil, fmt.Errorf("refund %s: not voided", contractAddress)
+	}
+	key := contractAddress + "/" + strings.ToLower(bettor)
+	if f.refunded[key] {
+		return nil, fmt.Errorf("refund %s to %s: no stake", contractAddress, bettor)
+	}
+	f.refunded[key] = true
+
+	n := len(f.refunded)
+	return &Transaction{
+		TransactionID:   fmt.Sprintf("0.0.2@%d.000000003", n),
+		TransactionHash: fmt.Sprintf("0x%096x", 3<<32+n),
+	}, nil
+}
+
+// Fail makes settling, voiding and refunding the given contract fail.
+func (f *FakeSettler) Fail(contractAddress string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing[contractAddress] = true
+}
+
+// Winner returns the outcome a contract was settled with.
+func (f *FakeSettler) Winner(contractAddress string) (stores.Outcome, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	winner, ok := f.settled[contractAddress]
+	return winner, ok
+}
@@ -0,0 +1,327 @@
+package contracts
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/mirror"
+	hiero "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+const (
+	// DefaultArtifactPath is where hardhat writes the compiled contract, relative to the server directory.
+	DefaultArtifactPath = "../contracts/artifacts/contracts/FPLMatchupBet.sol/FPLMatchupBet.json"
+	DefaultDeployGas    = 3_000_000
+)
+
+// Artifact is the subset of a hardhat build artifact the deployer needs.
+type Artifact struct {
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+	Bytecode     string          `json:"bytecode"`
+}
+
+// LoadArtifact reads a hardhat artifact and decodes its creation bytecode.
+func LoadArtifact(path string) (*Artifact, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read contract artifact: %w", err)
+	}
+	var artifact Artifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse contract artifact %s: %w", path, err)
+	}
+	bytecode, err := hex.DecodeString(strings.TrimPrefix(artifact.Bytecode, "0x"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid bytecode in contract artifact %s: %w", path, err)
+	}
+	if len(bytecode) == 0 {
+		return nil, nil, fmt.Errorf("contract artifact %s has no bytecode", path)
+	}
+	return &artifact, bytecode, nil
+}
+
+// bytecodeChunk is how much bytecode goes into the file create; the rest is appended.
+const bytecodeChunk = 2048
+
+const (
+	// bytecodeFileLifetime is how long an uploaded bytecode file stays on the network.
+	bytecodeFileLifetime = 90 * 24 * time.Hour
+	// bytecodeFileReuse is how long deployments share one upload before it is replaced,
+	// well ahead of the file expiring.
+	bytecodeFileReuse = 30 * 24 * time.Hour
+)
+
+// HieroDeployer deploys FPLMatchupBet directly through the Hiero SDK. The operator configured
+// on Client pays for the deployments and becomes the contract owner.
+//
+// Deployments without OnSubmit go through ContractCreateFlow, which uploads the bytecode to a
+// file and creates the contract from it. OnSubmit needs the contract create's transaction ID
+// before it is sent, which ContractCreateFlow cannot take, so those deployments build the
+// create by hand from a bytecode file that is uploaded once per process and shared until it
+// is replaced. The replaced file is deleted.
+//
+// The bytecode is read from ArtifactPath on the first deployment, so the server starts
+// without a compiled contract; set Bytecode to skip the artifact. Lost deployments are looked
+// up on Mirror.
+type HieroDeployer struct {
+	Client       *hiero.Client
+	Mirror       *mirror.Client
+	ArtifactPath string
+	Bytecode     []byte
+	Gas          int64
+
+	mu sync.Mutex
+
+	fileMu        sync.Mutex
+	file          *hiero.FileID
+	fileReplaceAt time.Time
+}
+
+func NewHieroDeployer(client *hiero.Client, mirrorClient *mirror.Client, artifactPath string) *HieroDeployer {
+	if artifactPath == "" {
+		artifactPath = DefaultArtifactPath
+	}
+	return &HieroDeployer{
+		Client:       client,
+		Mirror:       mirrorClient,
+		ArtifactPath: artifactPath,
+		Gas:          DefaultDeployGas,
+	}
+}
+
+// bytecode loads the contract bytecode on first use. A failed load is retried next time.
+func (d *HieroDeployer) bytecode() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.Bytecode == nil {
+		_, bytecode, err := LoadArtifact(d.ArtifactPath)
+		if err != nil {
+			return nil, err
+		}
+		d.Bytecode = bytecode
+	}
+	return d.Bytecode, nil
+}
+
+// Deploy stops between steps once ctx is done and stops waiting on a step that outlives it.
+// The SDK takes no context, so a step already sent to the network may still go through.
+func (d *HieroDeployer) Deploy(ctx context.Context, params DeployParams) (*Deployment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if params.VirtualPoolA == nil || params.VirtualPoolDraw == nil || params.VirtualPoolB == nil {
+		return nil, errors.New("virtual pools are required")
+	}
+	constructorParams := hiero.NewContractFunctionParameters().
+		AddUint256BigInt(big.NewInt(params.BettingEnd.Unix())).
+		AddUint256BigInt(params.VirtualPoolA).
+		AddUint256BigInt(params.VirtualPoolDraw).
+		AddUint256BigInt(params.VirtualPoolB)
+
+	if params.OnSubmit == nil {
+		return d.deployWithFlow(ctx, constructorParams)
+	}
+
+	fileID, err := d.bytecodeFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transactionID := hiero.TransactionIDGenerate(d.Client.GetOperatorAccountID())
+	contractCreate := hiero.NewContractCreateTransaction().
+		SetTransactionID(transactionID).
+		SetBytecodeFileID(fileID).
+		SetGas(uint64(d.Gas)).
+		SetConstructorParameters(constructorParams).
+		SetContractMemo("FPLMatchupBet")
+	if err := params.OnSubmit(transactionID.String()); err != nil {
+		return nil, fmt.Errorf("failed to record contract create %s: %w", transactionID, err)
+	}
+
+	var resp hiero.TransactionResponse
+	receipt, err := d.submit(ctx, func(client *hiero.Client) (hiero.TransactionResponse, error) {
+		var err error
+		resp, err = contractCreate.Execute(client)
+		return resp, err
+	})
+	if err != nil {
+		if fileGone(err) {
+			d.forgetFile(fileID)
+		}
+		return nil, fmt.Errorf("failed to create contract: %w", err)
+	}
+	return newDeployment(resp, receipt)
+}
+
+func (d *HieroDeployer) deployWithFlow(ctx context.Context, constructorParams *hiero.ContractFunctionParameters) (*Deployment, error) {
+	bytecode, err := d.bytecode()
+	if err != nil {
+		return nil, err
+	}
+	flow := hiero.NewContractCreateFlow().
+		SetBytecode(bytecode).
+		SetGas(d.Gas).
+		SetConstructorParameters(constructorParams).
+		SetContractMemo("FPLMatchupBet")
+
+	var resp hiero.TransactionResponse
+	receipt, err := d.submit(ctx, func(client *hiero.Client) (hiero.TransactionResponse, error) {
+		var err error
+		resp, err = flow.Execute(client)
+		return resp, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contract: %w", err)
+	}
+	return newDeployment(resp, receipt)
+}
+
+func newDeployment(resp hiero.TransactionResponse, receipt hiero.TransactionReceipt) (*Deployment, error) {
+	if receipt.ContractID == nil {
+		return nil, fmt.Errorf("contract create receipt for %s has no contract ID", resp.TransactionID)
+	}
+	return &Deployment{
+		ContractAddress: "0x" + receipt.ContractID.ToEvmAddress(),
+		ContractID:      receipt.ContractID.String(),
+		TransactionID:   resp.TransactionID.String(),
+		TransactionHash: "0x" + hex.EncodeToString(resp.Hash),
+	}, nil
+}
+
+// bytecodeFile returns the shared bytecode file, uploading it on first use and again once
+// bytecodeFileReuse has passed. The file it replaces is deleted; a file that cannot be
+// deleted expires on its own.
+func (d *HieroDeployer) bytecodeFile(ctx context.Context) (hiero.FileID, error) {
+	d.fileMu.Lock()
+	defer d.fileMu.Unlock()
+	if d.file != nil && time.Now().Before(d.fileReplaceAt) {
+		return *d.file, nil
+	}
+
+	bytecode, err := d.bytecode()
+	if err != nil {
+		return hiero.FileID{}, err
+	}
+	head, tail := bytecode, []byte(nil)
+	if len(bytecode) > bytecodeChunk {
+		head, tail = bytecode[:bytecodeChunk], bytecode[bytecodeChunk:]
+	}
+	now := time.Now()
+	fileCreate := hiero.NewFileCreateTransaction().
+		SetKeys(d.Client.GetOperatorPublicKey()).
+		SetExpirationTime(now.Add(bytecodeFileLifetime)).
+		SetContents(head)
+	receipt, err := d.submit(ctx, fileCreate.Execute)
+	if err != nil {
+		return hiero.FileID{}, fmt.Errorf("failed to upload contract bytecode: %w", err)
+	}
+	if receipt.FileID == nil {
+		return hiero.FileID{}, errors.New("bytecode file create receipt has no file ID")
+	}
+	fileID := *receipt.FileID
+
+	if len(tail) > 0 {
+		fileAppend := hiero.NewFileAppendTransaction().
+			SetFileID(fileID).
+			SetContents(tail)
+		if _, err := d.submit(ctx, fileAppend.Execute); err != nil {
+			d.deleteFile(ctx, fileID)
+			return hiero.FileID{}, fmt.Errorf("failed to append contract bytecode to %s: %w", fileID, err)
+		}
+	}
+
+	if d.file != nil {
+		d.deleteFile(ctx, *d.file)
+	}
+	d.file = &fileID
+	d.fileReplaceAt = now.Add(bytecodeFileReuse)
+	return fileID, nil
+}
+
+// forgetFile drops the shared bytecode file so the next deployment uploads a new one.
+func (d *HieroDeployer) forgetFile(fileID hiero.FileID) {
+	d.fileMu.Lock()
+	defer d.fileMu.Unlock()
+	if d.file != nil && d.file.String() == fileID.String() {
+		d.file = nil
+	}
+}
+
+func (d *HieroDeployer) deleteFile(ctx context.Context, fileID hiero.FileID) {
+	fileDelete := hiero.NewFileDeleteTransaction().SetFileID(fileID)
+	d.submit(ctx, fileDelete.Execute)
+}
+
+// fileGone reports whether a contract create failed because its bytecode file no longer exists.
+func fileGone(err error) bool {
+	var status hiero.Status
+	var receiptErr hiero.ErrHederaReceiptStatus
+	var precheckErr hiero.ErrHederaPreCheckStatus
+	switch {
+	case errors.As(err, &receiptErr):
+		status = receiptErr.Status
+	case errors.As(err, &precheckErr):
+		status = precheckErr.Status
+	default:
+		return false
+	}
+	return status == hiero.StatusInvalidFileID || status == hiero.StatusFileDeleted
+}
+
+// FindDeployment looks a contract create up on the mirror node. It returns nil if the
+// transaction has not reached the mirror node or did not create a contract.
+func (d *HieroDeployer) FindDeployment(ctx context.Context, transactionID string) (*Deployment, error) {
+	result, err := d.Mirror.GetContractResult(ctx, mirror.TransactionID(transactionID))
+	if errors.Is(err, mirror.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.Result != mirror.ResultSuccess || result.ContractID == "" {
+		return nil, nil
+	}
+	return &Deployment{
+		ContractAddress: result.Address,
+		ContractID:      result.ContractID,
+		TransactionID:   transactionID,
+		TransactionHash: result.Hash,
+	}, nil
+}
+
+// submit executes a transaction and waits for its successful receipt, giving up when ctx is
+// done first.
+func (d *HieroDeployer) submit(ctx context.Context, execute func(*hiero.Client) (hiero.TransactionResponse, error)) (hiero.TransactionReceipt, error) {
+	if err := ctx.Err(); err != nil {
+		return hiero.TransactionReceipt{}, err
+	}
+	type result struct {
+		receipt hiero.TransactionReceipt
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := execute(d.Client)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		receipt, err := resp.SetValidateStatus(true).GetReceipt(d.Client)
+		done <- result{receipt: receipt, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return hiero.TransactionReceipt{}, ctx.Err()
+	case r := <-done:
+		return r.receipt, r.err
+	}
+}
@@ -0,0 +1,31 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	hiero "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+func TestFileGone(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "deleted file in receipt", err: hiero.ErrHederaReceiptStatus{Status: hiero.StatusFileDeleted}, want: true},
+		{name: "invalid file in precheck", err: hiero.ErrHederaPreCheckStatus{Status: hiero.StatusInvalidFileID}, want: true},
+		{name: "wrapped", err: fmt.Errorf("create: %w", hiero.ErrHederaReceiptStatus{Status: hiero.StatusInvalidFileID}), want: true},
+		{name: "out of gas", err: hiero.ErrHederaReceiptStatus{Status: hiero.StatusInsufficientGas}},
+		{name: "busy node", err: hiero.ErrHederaPreCheckStatus{Status: hiero.StatusBusy}},
+		{name: "other error", err: errors.New("connection reset")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fileGone(tt.err); got != tt.want {
+				t.Errorf("fileGone(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const DefaultNodeDeployerURL = "http://localhost:3000/create"
+
+// NodeDeploymentRequest is the body the contract server's POST /create expects. Pools are
+// decimal HBAR strings; the server converts them with ethers.parseEther.
+type NodeDeploymentRequest struct {
+	VirtualPoolA        string `json:"virtualPoolA"`
+	VirtualPoolDraw     string `json:"virtualPoolDraw"`
+	VirtualPoolB        string `json:"virtualPoolB"`
+	BettingEndTimestamp int64  `json:"bettingEndTimestamp"`
+}
+
+type NodeDeploymentResponse struct {
+	Success         bool   `json:"success"`
+	ContractAddress string `json:"contractAddress"`
+	TransactionHash string `json:"transactionHash"`
+	Message         string `json:"message"`
+	BettingEnd      string `json:"bettingEnd"`
+}
+
+// NodeDeployer deploys through the Node/Express contract server in contracts/backend.
+type NodeDeployer struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func NewNodeDeployer(url string) *NodeDeployer {
+	if url == "" {
+		url = DefaultNodeDeployerURL
+	}
+	return &NodeDeployer{
+		URL:        url,
+		HTTPClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+func (d *NodeDeployer) Deploy(ctx context.Context, params DeployParams) (*Deployment, error) {
+	deploymentReq := NodeDeploymentRequest{
+		VirtualPoolA:        FormatEther(params.VirtualPoolA),
+		VirtualPoolDraw:     FormatEther(params.VirtualPoolDraw),
+		VirtualPoolB:        FormatEther(params.VirtualPoolB),
+		BettingEndTimestamp: params.BettingEnd.Unix(),
+	}
+
+	reqBody, err := json.Marshal(deploymentReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal deployment request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call contract deployment server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("contract deployment server returned status %d", resp.StatusCode)
+	}
+
+	var deploymentResp NodeDeploymentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&deploymentResp); err != nil {
+		return nil, fmt.Errorf("failed to parse deployment response: %v", err)
+	}
+
+	if !deploymentResp.Success {
+		return nil, fmt.Errorf("contract deployment failed: %s", deploymentResp.Message)
+	}
+
+	return &Deployment{
+		ContractAddress: deploymentResp.ContractAddress,
+		TransactionHash: deploymentResp.TransactionHash,
+	}, nil
+}
+
+// FindDeployment always returns nil: the contract server only reports a transaction once it
+// has been mined, so there is never a submitted transaction to look up.
+func (d *NodeDeployer) FindDeployment(ctx context.Context, transactionID string) (*Deployment, error) {
+	return nil, nil
+}
@@ -0,0 +1,97 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// FeePercent is the share of the real pool FPLMatchupBet keeps when it is settled.
+const FeePercent = stores.FeePercent
+
+// Pools are an FPLMatchupBet's per-outcome pools, wei-scaled like the contract's.
+type Pools struct {
+	A    *big.Int `json:"a"`
+	Draw *big.Int `json:"draw"`
+	B    *big.Int `json:"b"`
+}
+
+// NewPools returns zero pools.
+func NewPools() Pools {
+	return Pools{A: new(big.Int), Draw: new(big.Int), B: new(big.Int)}
+}
+
+// Pool returns the pool of one outcome.
+func (p Pools) Pool(outcome stores.Outcome) *big.Int {
+	switch outcome {
+	case stores.OutcomeHome:
+		return p.A
+	case stores.OutcomeDraw:
+		return p.Draw
+	default:
+		return p.B
+	}
+}
+
+func (p Pools) Total() *big.Int {
+	total := new(big.Int).Add(p.A, p.Draw)
+	return total.Add(total, p.B)
+}
+
+// Add returns the pools with amount added to one outcome's pool, as a bet would.
+func (p Pools) Add(outcome stores.Outcome, amount *big.Int) Pools {
+	sum := Pools{A: new(big.Int).Set(p.A), Draw: new(big.Int).Set(p.Draw), B: new(big.Int).Set(p.B)}
+	pool := sum.Pool(outcome)
+	pool.Add(pool, amount)
+	return sum
+}
+
+// SetVirtualPools sets the virtual pools to deploy a contract with.
+func (p *DeployParams) SetVirtualPools(pools Pools) {
+	p.VirtualPoolA, p.VirtualPoolDraw, p.VirtualPoolB = pools.A, pools.Draw, pools.B
+}
+
+// Odds reproduces getOdds: (virtualTotal + totalPool) * 1e18 / (virtualPool + pool) for the
+// outcome, or 0 if both pools are empty. The result is 1e18-scaled decimal odds.
+func Odds(virtual, real Pools, outcome stores.Outcome) *big.Int {
+	effectivePool := new(big.Int).Add(virtual.Pool(outcome), real.Pool(outcome))
+	effectiveTotal := new(big.Int).Add(virtual.Total(), real.Total())
+	if effectivePool.Sign() == 0 || effectiveTotal.Sign() == 0 {
+		return new(big.Int)
+	}
+	odds := new(big.Int).Mul(effectiveTotal, WeiPerHbar)
+	return odds.Quo(odds, effectivePool)
+}
+
+// ImpliedProbability is the inverse of Odds: the outcome's share of the effective pool.
+func ImpliedProbability(virtual, real Pools, outcome stores.Outcome) float64 {
+	effectivePool := new(big.Int).Add(virtual.Pool(outcome), real.Pool(outcome))
+	effectiveTotal := new(big.Int).Add(virtual.Total(), real.Total())
+	if effectiveTotal.Sign() == 0 {
+		return 0
+	}
+	probability, _ := new(big.Rat).SetFrac(effectivePool, effectiveTotal).Float64()
+	return probability
+}
+
+// Payout reproduces claim for a winning stake that is already part of real: the stake's share
+// of the real pool after the fee. Virtual pools only shape the odds and pay nothing out. The
+// result is wei-scaled; the contract transfers it divided by WeiPerTinybar.
+func Payout(real Pools, winner stores.Outcome, stake *big.Int) *big.Int {
+	winningPool := real.Pool(winner)
+	if winningPool.Sign() == 0 {
+		return new(big.Int)
+	}
+	total := real.Total()
+	fee := new(big.Int).Mul(total, big.NewInt(FeePercent))
+	fee.Quo(fee, big.NewInt(100))
+	netPool := total.Sub(total, fee)
+
+	payout := new(big.Int).Mul(stake, netPool)
+	return payout.Quo(payout, winningPool)
+}
+
+// TinybarsToWei scales tinybars the way the contract scales msg.value.
+func TinybarsToWei(tinybars int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(tinybars), WeiPerTinybar)
+}
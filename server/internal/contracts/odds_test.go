@@ -0,0 +1,100 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// hbarPools returns wei-scaled pools of whole HBAR.
+func hbarPools(home, draw, away int64) Pools {
+	return Pools{A: HbarToWei(home), Draw: HbarToWei(draw), B: HbarToWei(away)}
+}
+
+// odds parses 1e18-scaled decimal odds written as HBAR, e.g. "2.5".
+func odds(t *testing.T, s string) *big.Int {
+	t.Helper()
+	tinybars, err := ParseHbar(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return TinybarsToWei(tinybars)
+}
+
+func TestOdds(t *testing.T) {
+	virtual := hbarPools(100, 50, 100)
+	tests := []struct {
+		name    string
+		virtual Pools
+		real    Pools
+		outcome stores.Outcome
+		want    string
+	}{
+		{"home on virtual pools only", virtual, NewPools(), stores.OutcomeHome, "2.5"},
+		{"draw on virtual pools only", virtual, NewPools(), stores.OutcomeDraw, "5"},
+		{"bets shorten the backed outcome", virtual, hbarPools(150, 0, 0), stores.OutcomeHome, "1.6"},
+		{"bets lengthen the others", virtual, hbarPools(150, 0, 0), stores.OutcomeAway, "4"},
+		{"no virtual pools", NewPools(), hbarPools(30, 10, 60), stores.OutcomeDraw, "10"},
+		{"empty pools", NewPools(), NewPools(), stores.OutcomeHome, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Odds(tt.virtual, tt.real, tt.outcome)
+			if want := odds(t, tt.want); got.Cmp(want) != 0 {
+				t.Errorf("Odds = %s, want %s", FormatEther(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestPayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		real   Pools
+		winner stores.Outcome
+		stake  int64
+		want   string
+	}{
+		{"sole winner takes the pool less the fee", hbarPools(10, 0, 10), stores.OutcomeHome, 10, "19.6"},
+		{"winners share in proportion to stake", hbarPools(10, 5, 15), stores.OutcomeAway, 5, "9.8"},
+		{"winner with no losers gets the stake less the fee", hbarPools(0, 8, 0), stores.OutcomeDraw, 8, "7.84"},
+		{"empty winning pool pays nothing", hbarPools(10, 0, 10), stores.OutcomeDraw, 0, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Payout(tt.real, tt.winner, HbarToWei(tt.stake))
+			if want := odds(t, tt.want); got.Cmp(want) != 0 {
+				t.Errorf("Payout = %s HBAR, want %s", FormatEther(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHbar(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "2", want: 2 * TinybarsPerHbar},
+		{in: "2.5", want: 250_000_000},
+		{in: "0.00000001", want: 1},
+		{in: "0.000000001", wantErr: true},
+		{in: "two", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseHbar(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseHbar(%q) = %d, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseHbar(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package contracts
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+	hiero "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+const DefaultSettleGas = 200_000
+
+// Settlement describes a settle transaction.
+type Settlement struct {
+	Winner          stores.Outcome `json:"winner"`
+	TransactionID   string         `json:"transaction_id,omitempty"`
+	TransactionHash string         `json:"transaction_hash"`
+	SettledAt       time.Time      `json:"settled_at"`
+}
+
+// Transaction identifies an executed contract call.
+type Transaction struct {
+	TransactionID   string `json:"transaction_id,omitempty"`
+	TransactionHash string `json:"transaction_hash"`
+}
+
+// ContractSettler makes the owner's calls on a deployed FPLMatchupBet contract: settle once
+// betting has closed, or void an unsettled contract and refund its bettors. Contracts
+// deployed before void() was added revert on Void and Refund.
+type ContractSettler interface {
+	Settle(ctx context.Context, contractAddress string, winner stores.Outcome) (*Settlement, error)
+	Void(ctx context.Context, contractAddress string) (*Transaction, error)
+	Refund(ctx context.Context, contractAddress, bettor string) (*Transaction, error)
+}
+
+// HieroSettler settles contracts with ContractExecuteTransaction, paid for and signed by the
+// operator configured on Client, which must be the account that deployed the contract.
+type HieroSettler struct {
+	Client *hiero.Client
+	Gas    uint64
+}
+
+func NewHieroSettler(client *hiero.Client) *HieroSettler {
+	return &HieroSettler{
+		Client: client,
+		Gas:    DefaultSettleGas,
+	}
+}
+
+func (s *HieroSettler) Settle(ctx context.Context, contractAddress string, winner stores.Outcome) (*Settlement, error) {
+	if err := winner.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.execute(ctx, contractAddress, "settle", hiero.NewContractFunctionParameters().AddUint8(uint8(winner)))
+	if err != nil {
+		return nil, err
+	}
+	return &Settlement{
+		Winner:          winner,
+		TransactionID:   tx.TransactionID,
+		TransactionHash: tx.TransactionHash,
+		SettledAt:       time.Now().UTC(),
+	}, nil
+}
+
+func (s *HieroSettler) Void(ctx context.Context, contractAddress string) (*Transaction, error) {
+	return s.execute(ctx, contractAddress, "void", nil)
+}
+
+func (s *HieroSettler) Refund(ctx context.Context, contractAddress, bettor string) (*Transaction, error) {
+	params, err := hiero.NewContractFunctionParameters().AddAddress(bettor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bettor address %q: %w", bettor, err)
+	}
+	return s.execute(ctx, contractAddress, "refund", params)
+}
+
+// execute calls function on the contract and waits for its receipt.
+func (s *HieroSettler) execute(ctx context.Context, contractAddress, function string, params *hiero.ContractFunctionParameters) (*Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	contractID, err := hiero.ContractIDFromEvmAddress(0, 0, strings.TrimPrefix(contractAddress, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address %q: %w", contractAddress, err)
+	}
+
+	resp, err := hiero.NewContractExecuteTransaction().
+		SetContractID(contractID).
+		SetGas(s.Gas).
+		SetFunction(function, params).
+		Execute(s.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s on %s: %w", function, contractAddress, err)
+	}
+
+	if _, err := resp.GetReceipt(s.Client); err != nil {
+		return nil, fmt.Errorf("%s on %s failed in %s: %w", function, contractAddress, resp.TransactionID, err)
+	}
+
+	return &Transaction{
+		TransactionID:   resp.TransactionID.String(),
+		TransactionHash: "0x" + hex.EncodeToString(resp.Hash),
+	}, nil
+}
@@ -0,0 +1,99 @@
+package fpl
+
+import (
+	"sync"
+	"time"
+)
+
+// Forever marks a response that never goes stale, e.g. picks for a finished gameweek.
+const Forever time.Duration = -1
+
+const (
+	BootstrapTTL         = 5 * time.Minute
+	EventStatusTTL       = time.Minute
+	MostValuableTeamsTTL = 10 * time.Minute
+	EntryTTL             = 10 * time.Minute
+	LeagueStandingsTTL   = 10 * time.Minute
+	LivePicksTTL         = time.Minute
+	LiveTTL              = time.Minute
+)
+
+type CacheEntry struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+	ExpiresAt    *time.Time // nil means the entry never expires
+	StoredAt     time.Time
+}
+
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return e.ExpiresAt == nil || now.Before(*e.ExpiresAt)
+}
+
+func (e *CacheEntry) setTTL(now time.Time, ttl time.Duration) {
+	e.StoredAt = now
+	if ttl == Forever {
+		e.ExpiresAt = nil
+		return
+	}
+	expiresAt := now.Add(ttl)
+	e.ExpiresAt = &expiresAt
+}
+
+// Cache stores raw FPL responses keyed by API path. Get returns nil, nil on a miss.
+type Cache interface {
+	Get(key string) (*CacheEntry, error)
+	Set(key string, entry *CacheEntry) error
+}
+
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]*CacheEntry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*CacheEntry)}
+}
+
+func (mc *MemoryCache) Get(key string) (*CacheEntry, error) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return mc.entries[key], nil
+}
+
+func (mc *MemoryCache) Set(key string, entry *CacheEntry) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.entries[key] = entry
+	return nil
+}
+
+// TieredCache reads through Front (usually memory) to Back (usually Postgres) and
+// writes to both, so restarts keep permanent entries without a round trip per hit.
+type TieredCache struct {
+	Front Cache
+	Back  Cache
+}
+
+func NewTieredCache(front, back Cache) *TieredCache {
+	return &TieredCache{Front: front, Back: back}
+}
+
+func (tc *TieredCache) Get(key string) (*CacheEntry, error) {
+	entry, err := tc.Front.Get(key)
+	if err != nil || entry != nil {
+		return entry, err
+	}
+	entry, err = tc.Back.Get(key)
+	if err != nil || entry == nil {
+		return entry, err
+	}
+	return entry, tc.Front.Set(key, entry)
+}
+
+func (tc *TieredCache) Set(key string, entry *CacheEntry) error {
+	if err := tc.Front.Set(key, entry); err != nil {
+		return err
+	}
+	return tc.Back.Set(key, entry)
+}
@@ -0,0 +1,200 @@
+package fpl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheEntryFresh(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		at   time.Time
+		want bool
+	}{
+		{name: "within ttl", ttl: time.Minute, at: now.Add(30 * time.Second), want: true},
+		{name: "at expiry", ttl: time.Minute, at: now.Add(time.Minute), want: false},
+		{name: "past expiry", ttl: time.Minute, at: now.Add(2 * time.Minute), want: false},
+		{name: "forever", ttl: Forever, at: now.Add(24 * 365 * time.Hour), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &CacheEntry{}
+			entry.setTTL(now, tt.ttl)
+			if got := entry.Fresh(tt.at); got != tt.want {
+				t.Errorf("Fresh() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// etagServer serves body under etag, answering 304 to a matching If-None-Match.
+func etagServer(t *testing.T, etag, body string) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	var full, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &full, &notModified
+}
+
+func TestClientCache(t *testing.T) {
+	tests := []struct {
+		name            string
+		expire          bool
+		wantFull        int32
+		wantNotModified int32
+	}{
+		{name: "fresh entry served without a request", wantFull: 1},
+		{name: "stale entry revalidated with its ETag", expire: true, wantFull: 1, wantNotModified: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, full, notModified := etagServer(t, `"v1"`, `{"id":1,"summary_overall_rank":10}`)
+			cache := NewMemoryCache()
+			client := NewClient(server.URL, server.Client())
+			client.Cache = cache
+			ctx := context.Background()
+
+			if _, err := client.GetEntry(ctx, 1); err != nil {
+				t.Fatalf("first GetEntry() = %v", err)
+			}
+			if tt.expire {
+				entry, _ := cache.Get("/entry/1/")
+				past := time.Now().Add(-time.Second)
+				entry.ExpiresAt = &past
+			}
+			entry, err := client.GetEntry(ctx, 1)
+			if err != nil {
+				t.Fatalf("second GetEntry() = %v", err)
+			}
+			if entry.SummaryOverallRank != 10 {
+				t.Errorf("overall rank = %d, want the cached 10", entry.SummaryOverallRank)
+			}
+			if full.Load() != tt.wantFull || notModified.Load() != tt.wantNotModified {
+				t.Errorf("full responses = %d, 304s = %d, want %d, %d", full.Load(), notModified.Load(), tt.wantFull, tt.wantNotModified)
+			}
+
+			cached, _ := cache.Get("/entry/1/")
+			if cached.ETag != `"v1"` || !cached.Fresh(time.Now()) {
+				t.Errorf("cached entry = %+v, want a fresh entry with ETag \"v1\"", cached)
+			}
+		})
+	}
+}
+
+func TestClientCacheLastModified(t *testing.T) {
+	const lastModified = "Sat, 20 Sep 2025 10:00:00 GMT"
+	var gotSince atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if since := r.Header.Get("If-Modified-Since"); since != "" {
+			gotSince.Store(since)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(`{"status":[{"event":5}]}`))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	client := NewClient(server.URL, server.Client())
+	client.Cache = cache
+	ctx := context.Background()
+
+	if _, err := client.GetEventStatus(ctx); err != nil {
+		t.Fatalf("GetEventStatus() = %v", err)
+	}
+	entry, _ := cache.Get("/event-status/")
+	past := time.Now().Add(-time.Second)
+	entry.ExpiresAt = &past
+
+	status, err := client.GetEventStatus(ctx)
+	if err != nil {
+		t.Fatalf("revalidated GetEventStatus() = %v", err)
+	}
+	if gotSince.Load() != lastModified {
+		t.Errorf("If-Modified-Since = %v, want %s", gotSince.Load(), lastModified)
+	}
+	if len(status.Status) != 1 || status.Status[0].Event != 5 {
+		t.Errorf("status = %+v, want the cached gameweek 5", status.Status)
+	}
+}
+
+// Picks for a gameweek that is finished and data checked are cached permanently.
+func TestClientCachesFinishedPicksForever(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bootstrap-static/":
+			w.Write([]byte(`{"events":[{"id":5,"finished":true,"data_checked":true},{"id":6}]}`))
+		default:
+			w.Write([]byte(`{"picks":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	client := NewClient(server.URL, server.Client())
+	client.Cache = cache
+	ctx := context.Background()
+
+	tests := []struct {
+		gameweek    int
+		wantForever bool
+	}{
+		{gameweek: 5, wantForever: true},
+		{gameweek: 6, wantForever: false},
+	}
+	for _, tt := range tests {
+		if _, err := client.GetEntryPicks(ctx, 1, tt.gameweek); err != nil {
+			t.Fatalf("GetEntryPicks(gameweek %d) = %v", tt.gameweek, err)
+		}
+		entry, _ := cache.Get(fmt.Sprintf("/entry/1/event/%d/picks/", tt.gameweek))
+		if entry == nil {
+			t.Fatalf("gameweek %d picks not cached", tt.gameweek)
+		}
+		if forever := entry.ExpiresAt == nil; forever != tt.wantForever {
+			t.Errorf("gameweek %d picks cached forever = %t, want %t", tt.gameweek, forever, tt.wantForever)
+		}
+	}
+}
+
+func TestTieredCache(t *testing.T) {
+	front, back := NewMemoryCache(), NewMemoryCache()
+	back.Set("/bootstrap-static/", &CacheEntry{Body: []byte(`{}`), ETag: `"b"`})
+	tiered := NewTieredCache(front, back)
+
+	entry, err := tiered.Get("/bootstrap-static/")
+	if err != nil || entry == nil || entry.ETag != `"b"` {
+		t.Fatalf("Get() = %+v, %v, want the back entry", entry, err)
+	}
+	if promoted, _ := front.Get("/bootstrap-static/"); promoted != entry {
+		t.Errorf("back entry not promoted to the front cache")
+	}
+
+	if err := tiered.Set("/event-status/", &CacheEntry{Body: []byte(`{}`)}); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	for name, cache := range map[string]Cache{"front": front, "back": back} {
+		if entry, _ := cache.Get("/event-status/"); entry == nil {
+			t.Errorf("%s cache missing the written entry", name)
+		}
+	}
+	if entry, _ := tiered.Get("/missing/"); entry != nil {
+		t.Errorf("Get(missing) = %+v, want nil", entry)
+	}
+}
@@ -0,0 +1,246 @@
+package fpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL   = "https://fantasy.premierleague.com/api"
+	DefaultUserAgent = "fplduel/1.0 (+https://github.com/divin3circle/fplduel)"
+	// DefaultTimeout covers a whole call including retries.
+	DefaultTimeout = 2 * time.Minute
+)
+
+// StatusError is returned when the FPL API answers with anything other than 200 OK.
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("fpl: %s returned status %d", e.URL, e.StatusCode)
+}
+
+// Client talks to the FPL API. BaseURL can point at a local fixture server
+// instead of fantasy.premierleague.com. When Cache is set, responses are kept
+// for a per-endpoint TTL and revalidated with ETag/Last-Modified once stale.
+type Client struct {
+	BaseURL    string
+	UserAgent  string
+	HTTPClient *http.Client
+	Cache      Cache
+
+	mu             sync.Mutex
+	finishedEvents map[int]bool
+}
+
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: NewRetryTransport(nil, DefaultLimiter),
+		}
+	}
+	return &Client{
+		BaseURL:        strings.TrimRight(baseURL, "/"),
+		UserAgent:      DefaultUserAgent,
+		HTTPClient:     httpClient,
+		finishedEvents: make(map[int]bool),
+	}
+}
+
+func (c *Client) GetBootstrapStatic(ctx context.Context) (*BootstrapData, error) {
+	var data BootstrapData
+	if err := c.getJSON(ctx, "/bootstrap-static/", BootstrapTTL, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) GetEventStatus(ctx context.Context) (*GameweekStatus, error) {
+	var data GameweekStatus
+	if err := c.getJSON(ctx, "/event-status/", EventStatusTTL, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) GetMostValuableTeams(ctx context.Context) ([]*ValuableTeam, error) {
+	var data []*ValuableTeam
+	if err := c.getJSON(ctx, "/stats/most-valuable-teams/", MostValuableTeamsTTL, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetEntry returns a manager's season summary, including their overall rank.
+func (c *Client) GetEntry(ctx context.Context, entryID int) (*Entry, error) {
+	var data Entry
+	if err := c.getJSON(ctx, fmt.Sprintf("/entry/%d/", entryID), EntryTTL, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetClassicLeagueStandings returns one page (1-based) of a classic league's standings.
+func (c *Client) GetClassicLeagueStandings(ctx context.Context, leagueID, page int) (*LeagueStandings, error) {
+	var data LeagueStandings
+	if err := c.getJSON(ctx, fmt.Sprintf("/leagues-classic/%d/standings/?page_standings=%d", leagueID, page), LeagueStandingsTTL, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) GetEntryPicks(ctx context.Context, entryID, gameweek int) (*EntryPicks, error) {
+	body, err := c.GetEntryPicksRaw(ctx, entryID, gameweek)
+	if err != nil {
+		return nil, err
+	}
+	var data EntryPicks
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("fpl: failed to decode picks: %w", err)
+	}
+	return &data, nil
+}
+
+// GetEntryPicksRaw returns the picks payload exactly as FPL sent it, for proxying to the frontend.
+// Picks for a finished gameweek never change, so those are cached permanently.
+func (c *Client) GetEntryPicksRaw(ctx context.Context, entryID, gameweek int) ([]byte, error) {
+	ttl := LivePicksTTL
+	if c.gameweekFinished(ctx, gameweek) {
+		ttl = Forever
+	}
+	return c.get(ctx, fmt.Sprintf("/entry/%d/event/%d/picks/", entryID, gameweek), ttl)
+}
+
+// GetEventLive returns live points for every player in a gameweek.
+func (c *Client) GetEventLive(ctx context.Context, gameweek int) (*EventLive, error) {
+	ttl := LiveTTL
+	if c.gameweekFinished(ctx, gameweek) {
+		ttl = Forever
+	}
+	var data EventLive
+	if err := c.getJSON(ctx, fmt.Sprintf("/event/%d/live/", gameweek), ttl, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetFixtures returns the fixtures scheduled in a gameweek.
+func (c *Client) GetFixtures(ctx context.Context, gameweek int) ([]*Fixture, error) {
+	ttl := LiveTTL
+	if c.gameweekFinished(ctx, gameweek) {
+		ttl = Forever
+	}
+	var data []*Fixture
+	if err := c.getJSON(ctx, fmt.Sprintf("/fixtures/?event=%d", gameweek), ttl, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// gameweekFinished reports whether FPL has finished and data-checked the gameweek.
+// Only positive answers are remembered; errors are treated as "not finished".
+func (c *Client) gameweekFinished(ctx context.Context, gameweek int) bool {
+	c.mu.Lock()
+	finished := c.finishedEvents[gameweek]
+	c.mu.Unlock()
+	if finished {
+		return true
+	}
+
+	data, err := c.GetBootstrapStatic(ctx)
+	if err != nil || data.Events == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, event := range *data.Events {
+		if event.Finished && event.DataChecked {
+			c.finishedEvents[event.ID] = true
+		}
+	}
+	return c.finishedEvents[gameweek]
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, ttl time.Duration, v any) error {
+	body, err := c.get(ctx, path, ttl)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("fpl: failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// get fetches path, serving it from Cache while fresh. The cache is best effort:
+// read or write failures fall back to talking to FPL directly.
+func (c *Client) get(ctx context.Context, path string, ttl time.Duration) ([]byte, error) {
+	var cached *CacheEntry
+	if c.Cache != nil {
+		cached, _ = c.Cache.Get(path)
+		if cached != nil && cached.Fresh(time.Now()) {
+			return cached.Body, nil
+		}
+	}
+
+	url := c.BaseURL + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		revalidated := *cached
+		revalidated.setTTL(time.Now(), ttl)
+		c.Cache.Set(path, &revalidated)
+		return revalidated.Body, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, URL: url}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Cache != nil {
+		entry := &CacheEntry{
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}
+		entry.setTTL(time.Now(), ttl)
+		c.Cache.Set(path, entry)
+	}
+	return body, nil
+}
@@ -0,0 +1,118 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(*Client) error
+		wantPath string
+		wantURI  string
+	}{
+		{
+			name:    "bootstrap-static",
+			call:    func(c *Client) error { _, err := c.GetBootstrapStatic(context.Background()); return err },
+			wantURI: "/api/bootstrap-static/",
+		},
+		{
+			name:    "event status",
+			call:    func(c *Client) error { _, err := c.GetEventStatus(context.Background()); return err },
+			wantURI: "/api/event-status/",
+		},
+		{
+			name:    "most valuable teams",
+			call:    func(c *Client) error { _, err := c.GetMostValuableTeams(context.Background()); return err },
+			wantURI: "/api/stats/most-valuable-teams/",
+		},
+		{
+			name:    "entry",
+			call:    func(c *Client) error { _, err := c.GetEntry(context.Background(), 42); return err },
+			wantURI: "/api/entry/42/",
+		},
+		{
+			name: "league standings",
+			call: func(c *Client) error {
+				_, err := c.GetClassicLeagueStandings(context.Background(), 314, 2)
+				return err
+			},
+			wantURI: "/api/leagues-classic/314/standings/?page_standings=2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotURI, gotAgent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotURI = r.URL.RequestURI()
+				gotAgent = r.Header.Get("User-Agent")
+				if r.URL.Path == "/api/stats/most-valuable-teams/" {
+					w.Write([]byte(`[]`))
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL+"/api/", server.Client())
+			if err := tt.call(client); err != nil {
+				t.Fatalf("call = %v", err)
+			}
+			if gotURI != tt.wantURI {
+				t.Errorf("requested %s, want %s", gotURI, tt.wantURI)
+			}
+			if gotAgent != DefaultUserAgent {
+				t.Errorf("User-Agent = %q, want %q", gotAgent, DefaultUserAgent)
+			}
+		})
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantStatus int
+	}{
+		{name: "not found", status: http.StatusNotFound, body: `{"detail":"Not found."}`, wantStatus: http.StatusNotFound},
+		{name: "game updating", status: http.StatusServiceUnavailable, body: `"The game is being updated."`, wantStatus: http.StatusServiceUnavailable},
+		{name: "malformed body", status: http.StatusOK, body: `{"id":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewClient(server.URL, server.Client()).GetEntry(context.Background(), 1)
+			if err == nil {
+				t.Fatalf("GetEntry() succeeded, want an error")
+			}
+			var status *StatusError
+			isStatus := errors.As(err, &status)
+			if tt.wantStatus == 0 {
+				if isStatus {
+					t.Errorf("GetEntry() = %v, want a decode error", err)
+				}
+				return
+			}
+			if !isStatus || status.StatusCode != tt.wantStatus {
+				t.Errorf("GetEntry() = %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	client := NewClient("", nil)
+	if client.BaseURL != DefaultBaseURL {
+		t.Errorf("BaseURL = %s, want %s", client.BaseURL, DefaultBaseURL)
+	}
+}
@@ -0,0 +1,95 @@
+package fpl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var fixtureNameReplacer = strings.NewReplacer("/", "_", "?", "__", "&", "_", "=", "-")
+
+// FixtureName maps an API path (relative to the base URL) to the file it is recorded under,
+// e.g. /entry/1/event/5/picks/ -> entry_1_event_5_picks.json.
+func FixtureName(path string) string {
+	return fixtureNameReplacer.Replace(strings.Trim(path, "/")) + ".json"
+}
+
+// Recorder is an http.RoundTripper that writes every successful FPL response to Dir
+// so it can later be served back by a replay server.
+type Recorder struct {
+	Dir      string
+	BasePath string
+	Next     http.RoundTripper
+}
+
+func NewRecorder(dir string, baseURL string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("fpl: failed to create fixtures dir: %w", err)
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("fpl: invalid base URL: %w", err)
+	}
+	return &Recorder{Dir: dir, BasePath: strings.TrimRight(base.Path, "/"), Next: next}, nil
+}
+
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rec.Next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	path := strings.TrimPrefix(req.URL.Path, rec.BasePath)
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+	if err := os.WriteFile(filepath.Join(rec.Dir, FixtureName(path)), body, 0o644); err != nil {
+		return nil, fmt.Errorf("fpl: failed to record %s: %w", path, err)
+	}
+	return resp, nil
+}
+
+// NewReplayHandler serves fixtures recorded by Recorder from dir. Unknown paths get a 404.
+func NewReplayHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if r.URL.RawQuery != "" {
+			path += "?" + r.URL.RawQuery
+		}
+
+		body, err := os.ReadFile(filepath.Join(dir, FixtureName(path)))
+		if os.IsNotExist(err) {
+			http.Error(w, `{"detail":"fixture not found"}`, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+}
+
+// NewReplayServer starts a local server that replays recorded fixtures. Point a Client's
+// BaseURL at server.URL to run without network access.
+func NewReplayServer(dir string) *httptest.Server {
+	return httptest.NewServer(NewReplayHandler(dir))
+}
@@ -0,0 +1,160 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFixtureName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/bootstrap-static/", want: "bootstrap-static.json"},
+		{path: "/entry/1/", want: "entry_1.json"},
+		{path: "/entry/1/event/5/picks/", want: "entry_1_event_5_picks.json"},
+		{path: "/fixtures/?event=5", want: "fixtures___event-5.json"},
+		{path: "/leagues-classic/314/standings/?page_standings=2", want: "leagues-classic_314_standings___page_standings-2.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := FixtureName(tt.path); got != tt.want {
+				t.Errorf("FixtureName(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// replayDir holds the checked-in fixtures that FPL_MODE=replay serves by default.
+var replayDir = filepath.Join("..", "..", "testdata", "fpl")
+
+// The checked-in fixtures are served back through a Client.
+func TestReplayFixtures(t *testing.T) {
+	server := NewReplayServer(replayDir)
+	defer server.Close()
+	client := NewClient(server.URL, server.Client())
+	ctx := context.Background()
+
+	bootstrap, err := client.GetBootstrapStatic(ctx)
+	if err != nil {
+		t.Fatalf("GetBootstrapStatic() = %v", err)
+	}
+	if bootstrap.Events == nil || len(*bootstrap.Events) != 3 {
+		t.Fatalf("events = %v, want 3", bootstrap.Events)
+	}
+	if next := (*bootstrap.Events)[2]; next.ID != 6 || !next.IsNext {
+		t.Errorf("next event = %+v, want gameweek 6", next)
+	}
+
+	status, err := client.GetEventStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetEventStatus() = %v", err)
+	}
+	if len(status.Status) == 0 || status.Status[0].Event != 5 {
+		t.Errorf("event status = %+v, want gameweek 5", status.Status)
+	}
+
+	teams, err := client.GetMostValuableTeams(ctx)
+	if err != nil {
+		t.Fatalf("GetMostValuableTeams() = %v", err)
+	}
+	if len(teams) != 10 {
+		t.Errorf("most valuable teams = %d, want 10", len(teams))
+	}
+	for _, team := range teams {
+		if _, err := client.GetEntry(ctx, team.EntryID); err != nil {
+			t.Errorf("GetEntry(%d) = %v, want every most valuable team recorded", team.EntryID, err)
+		}
+	}
+
+	entry, err := client.GetEntry(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetEntry() = %v", err)
+	}
+	if entry.SummaryOverallRank != 1204 {
+		t.Errorf("overall rank = %d, want 1204", entry.SummaryOverallRank)
+	}
+
+	picks, err := client.GetEntryPicks(ctx, 1, 5)
+	if err != nil {
+		t.Fatalf("GetEntryPicks() = %v", err)
+	}
+	if len(picks.Picks) != 15 {
+		t.Fatalf("picks = %d, want 15", len(picks.Picks))
+	}
+	if captain := picks.Picks[8]; !captain.IsCaptain || captain.Multiplier != 2 {
+		t.Errorf("pick 9 = %+v, want the captain", captain)
+	}
+	want := []AutomaticSub{{Entry: 1, ElementIn: 12, ElementOut: 1, Event: 5}}
+	if !reflect.DeepEqual(picks.AutomaticSubs, want) {
+		t.Errorf("automatic subs = %v, want %v", picks.AutomaticSubs, want)
+	}
+
+	var statusErr *StatusError
+	if _, err := client.GetEntry(ctx, 11); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetEntry(unrecorded) = %v, want a 404", err)
+	}
+}
+
+// Responses recorded through a Recorder are replayed unchanged, query strings included.
+func TestRecordReplay(t *testing.T) {
+	upstream := http.NewServeMux()
+	upstream.HandleFunc("/api/entry/7/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":7,"name":"Recorded","summary_overall_rank":55}`))
+	})
+	upstream.HandleFunc("/api/leagues-classic/314/standings/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"standings":{"has_next":false,"page":2,"results":[]}}`))
+	})
+	upstream.HandleFunc("/api/entry/8/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	live := httptest.NewServer(upstream)
+	defer live.Close()
+
+	dir := t.TempDir()
+	baseURL := live.URL + "/api"
+	recorder, err := NewRecorder(dir, baseURL, live.Client().Transport)
+	if err != nil {
+		t.Fatalf("NewRecorder() = %v", err)
+	}
+	ctx := context.Background()
+	recording := NewClient(baseURL, &http.Client{Transport: recorder})
+
+	recorded, err := recording.GetEntry(ctx, 7)
+	if err != nil {
+		t.Fatalf("GetEntry() while recording = %v", err)
+	}
+	if _, err := recording.GetClassicLeagueStandings(ctx, 314, 2); err != nil {
+		t.Fatalf("GetClassicLeagueStandings() while recording = %v", err)
+	}
+	if _, err := recording.GetEntry(ctx, 8); err == nil {
+		t.Fatalf("GetEntry(8) while recording succeeded, want an error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "entry_8.json")); !os.IsNotExist(err) {
+		t.Errorf("failed response was recorded")
+	}
+
+	replay := NewReplayServer(dir)
+	defer replay.Close()
+	replaying := NewClient(replay.URL, replay.Client())
+
+	replayed, err := replaying.GetEntry(ctx, 7)
+	if err != nil {
+		t.Fatalf("GetEntry() while replaying = %v", err)
+	}
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("replayed entry = %+v, want %+v", replayed, recorded)
+	}
+	if _, err := replaying.GetClassicLeagueStandings(ctx, 314, 2); err != nil {
+		t.Errorf("GetClassicLeagueStandings() while replaying = %v", err)
+	}
+	if _, err := replaying.GetClassicLeagueStandings(ctx, 314, 3); err == nil {
+		t.Errorf("unrecorded page replayed, want a 404")
+	}
+}
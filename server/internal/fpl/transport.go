@@ -0,0 +1,186 @@
+package fpl
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRequestsPerSecond = 4
+	DefaultBurst             = 8
+	DefaultMaxRetries        = 4
+	DefaultBaseDelay         = 500 * time.Millisecond
+	DefaultMaxDelay          = 10 * time.Second
+	// DefaultMaxRetryAfter caps how long we are willing to wait on a Retry-After header
+	// before giving up and handing the response back to the caller.
+	DefaultMaxRetryAfter = 30 * time.Second
+	// DefaultAttemptTimeout bounds a single attempt waiting for response headers.
+	DefaultAttemptTimeout = 20 * time.Second
+)
+
+// DefaultLimiter is shared by every Client created with NewClient so the whole server
+// stays under one request budget towards FPL.
+var DefaultLimiter = NewRateLimiter(DefaultRequestsPerSecond, DefaultBurst)
+
+// RateLimiter is a token bucket refilled at Rate tokens per second up to Burst tokens.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// RetryTransport retries idempotent requests that fail with a network error or a
+// 429/5xx status, using exponential backoff with jitter and honouring Retry-After.
+// Every attempt takes a token from Limiter first.
+type RetryTransport struct {
+	Next          http.RoundTripper
+	Limiter       *RateLimiter
+	MaxRetries    int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	MaxRetryAfter time.Duration
+}
+
+func NewRetryTransport(next http.RoundTripper, limiter *RateLimiter) *RetryTransport {
+	if next == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.ResponseHeaderTimeout = DefaultAttemptTimeout
+		next = transport
+	}
+	return &RetryTransport{
+		Next:          next,
+		Limiter:       limiter,
+		MaxRetries:    DefaultMaxRetries,
+		BaseDelay:     DefaultBaseDelay,
+		MaxDelay:      DefaultMaxDelay,
+		MaxRetryAfter: DefaultMaxRetryAfter,
+	}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	for attempt := 0; ; attempt++ {
+		if t.Limiter != nil {
+			if err := t.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.Next.RoundTrip(req)
+		if !idempotent || attempt >= t.MaxRetries || !retryable(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if retryAfter > t.MaxRetryAfter {
+					return resp, nil
+				}
+				if retryAfter > delay {
+					delay = retryAfter
+				}
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	delay := t.BaseDelay << attempt
+	if delay <= 0 || delay > t.MaxDelay {
+		delay = t.MaxDelay
+	}
+	// equal jitter: half fixed, half random
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter handles both the delay-seconds and HTTP-date forms of Retry-After.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		delay := time.Until(at)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
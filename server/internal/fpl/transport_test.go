@@ -0,0 +1,233 @@
+package fpl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries returns a RetryTransport with delays short enough for tests and no rate limit.
+func fastRetries(limiter *RateLimiter) *RetryTransport {
+	transport := NewRetryTransport(http.DefaultTransport, limiter)
+	transport.BaseDelay = time.Millisecond
+	transport.MaxDelay = 5 * time.Millisecond
+	return transport
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		method       string
+		wantStatus   int
+		wantAttempts int
+	}{
+		{name: "success", statuses: []int{200}, wantStatus: 200, wantAttempts: 1},
+		{name: "retries 429", statuses: []int{429, 200}, wantStatus: 200, wantAttempts: 2},
+		{name: "retries 503", statuses: []int{503, 503, 200}, wantStatus: 200, wantAttempts: 3},
+		{name: "retries 502 and 504", statuses: []int{502, 504, 200}, wantStatus: 200, wantAttempts: 3},
+		{name: "gives up after max retries", statuses: []int{503, 503, 503, 503, 503, 503}, wantStatus: 503, wantAttempts: DefaultMaxRetries + 1},
+		{name: "does not retry 404", statuses: []int{404, 200}, wantStatus: 404, wantAttempts: 1},
+		{name: "does not retry POST", statuses: []int{503, 200}, method: http.MethodPost, wantStatus: 503, wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1)) - 1
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses)-1)])
+			}))
+			defer server.Close()
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, _ := http.NewRequest(method, server.URL, nil)
+			resp, err := fastRetries(nil).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := int(attempts.Load()); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	tests := []struct {
+		name         string
+		retryAfter   string
+		maxWait      time.Duration
+		wantStatus   int
+		wantAttempts int
+		minElapsed   time.Duration
+	}{
+		{name: "waits for delay seconds", retryAfter: "1", maxWait: time.Minute, wantStatus: 200, wantAttempts: 2, minElapsed: time.Second},
+		{name: "gives up on a wait beyond the cap", retryAfter: "120", maxWait: time.Minute, wantStatus: 429, wantAttempts: 1},
+		{name: "past HTTP date retries at once", retryAfter: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), maxWait: time.Minute, wantStatus: 200, wantAttempts: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) == 1 {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+				}
+			}))
+			defer server.Close()
+
+			transport := fastRetries(nil)
+			transport.MaxRetryAfter = tt.maxWait
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			start := time.Now()
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus || int(attempts.Load()) != tt.wantAttempts {
+				t.Errorf("status %d after %d attempts, want %d after %d", resp.StatusCode, attempts.Load(), tt.wantStatus, tt.wantAttempts)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("retried after %s, want at least %s", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestRetryTransportContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := fastRetries(nil).RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoundTrip() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "0", want: 0, wantOK: true},
+		{value: "30", want: 30 * time.Second, wantOK: true},
+		{value: "-5", wantOK: false},
+		{value: "soon", wantOK: false},
+		{value: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), want: 0, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoffCapped(t *testing.T) {
+	transport := &RetryTransport{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 0; attempt < 8; attempt++ {
+		full := min(transport.BaseDelay<<attempt, transport.MaxDelay)
+		got := transport.backoff(attempt)
+		if got < full/2 || got > full {
+			t.Errorf("backoff(%d) = %s, want between %s and %s", attempt, got, full/2, full)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	tests := []struct {
+		rate       float64
+		burst      int
+		requests   int
+		minElapsed time.Duration
+	}{
+		{rate: 10, burst: 5, requests: 5},
+		{rate: 20, burst: 2, requests: 6, minElapsed: 200 * time.Millisecond},
+		{rate: 100, burst: 1, requests: 11, minElapsed: 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.burst)+"@"+strconv.FormatFloat(tt.rate, 'f', -1, 64), func(t *testing.T) {
+			limiter := NewRateLimiter(tt.rate, tt.burst)
+			start := time.Now()
+			for i := 0; i < tt.requests; i++ {
+				if err := limiter.Wait(context.Background()); err != nil {
+					t.Fatalf("Wait() = %v", err)
+				}
+			}
+			elapsed := time.Since(start)
+			if elapsed < tt.minElapsed {
+				t.Errorf("%d requests took %s, want at least %s", tt.requests, elapsed, tt.minElapsed)
+			}
+			if tt.minElapsed == 0 && elapsed > 50*time.Millisecond {
+				t.Errorf("burst of %d requests took %s, want no wait", tt.requests, elapsed)
+			}
+		})
+	}
+}
+
+func TestRateLimiterContextCancelled(t *testing.T) {
+	limiter := NewRateLimiter(0.1, 1)
+	limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() on an empty bucket = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRetryTransportTakesTokens(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	// one token up front, then one every 50ms: the two retries must each wait for a token
+	limiter := NewRateLimiter(20, 1)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	start := time.Now()
+	resp, err := fastRetries(limiter).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() = %v", err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("three attempts took %s, want each retry to wait for a token", elapsed)
+	}
+}
+
+func TestNewClientSharesDefaultLimiter(t *testing.T) {
+	client := NewClient("", nil)
+	transport, ok := client.HTTPClient.Transport.(*RetryTransport)
+	if !ok {
+		t.Fatalf("transport = %T, want *RetryTransport", client.HTTPClient.Transport)
+	}
+	if transport.Limiter != DefaultLimiter {
+		t.Errorf("default client does not share DefaultLimiter")
+	}
+}
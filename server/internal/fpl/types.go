@@ -0,0 +1,244 @@
+package fpl
+
+import "time"
+
+type Overrides struct {
+	Rules          map[string]any `json:"rules"`
+	Scoring        map[string]any `json:"scoring"`
+	ElementTypes   []any          `json:"element_types"`
+	PickMultiplier *int           `json:"pick_multiplier"`
+}
+
+type Chip struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Number     int       `json:"number"`
+	StartEvent int       `json:"start_event"`
+	StopEvent  int       `json:"stop_event"`
+	ChipType   string    `json:"chip_type"`
+	Overrides  Overrides `json:"overrides"`
+}
+
+type Chips []Chip
+
+type BootstrapTeam struct {
+	Code                int     `json:"code"`
+	Draw                int     `json:"draw"`
+	Form                *string `json:"form"`
+	ID                  int     `json:"id"`
+	Loss                int     `json:"loss"`
+	Name                string  `json:"name"`
+	Played              int     `json:"played"`
+	Points              int     `json:"points"`
+	Position            int     `json:"position"`
+	ShortName           string  `json:"short_name"`
+	Strength            int     `json:"strength"`
+	TeamDivision        *string `json:"team_division"`
+	Unavailable         bool    `json:"unavailable"`
+	Win                 int     `json:"win"`
+	StrengthOverallHome int     `json:"strength_overall_home"`
+	StrengthOverallAway int     `json:"strength_overall_away"`
+	StrengthAttackHome  int     `json:"strength_attack_home"`
+	StrengthAttackAway  int     `json:"strength_attack_away"`
+	StrengthDefenceHome int     `json:"strength_defence_home"`
+	StrengthDefenceAway int     `json:"strength_defence_away"`
+	PulseID             int     `json:"pulse_id"`
+}
+
+type BootstrapTeams []BootstrapTeam
+
+type Element struct {
+	ID                         int        `json:"id"`
+	Name                       string     `json:"name"`
+	WebName                    string     `json:"web_name"`
+	TeamID                     int        `json:"team_id"`
+	TeamCode                   int        `json:"team_code"`
+	InDreamteam                bool       `json:"in_dreamteam"`
+	TotalPoints                int        `json:"total_points"`
+	Code                       int        `json:"code"`
+	Photo                      *string    `json:"photo,omitempty"`
+	BirthDate                  *string    `json:"birth_date,omitempty"`
+	TeamJoinedDate             *string    `json:"team_joined_date,omitempty"`
+	MinutesPlayed              int        `json:"minutes_played"`
+	FormRank                   int        `json:"form_rank"`
+	Form                       string     `json:"form"`
+	PointsPerGame              string     `json:"points_per_game"`
+	Influence                  string     `json:"influence"`
+	Creativity                 string     `json:"creativity"`
+	Threat                     string     `json:"threat"`
+	IctIndex                   string     `json:"ict_index"`
+	ElementType                int        `json:"element_type"`
+	TransfersIn                int        `json:"transfers_in"`
+	TransfersOut               int        `json:"transfers_out"`
+	SelectedByPercent          string     `json:"selected_by_percent"`
+	SelectedRank               int        `json:"selected_rank"`
+	PointsPerGameRank          int        `json:"points_per_game_rank"`
+	IctIndexRank               int        `json:"ict_index_rank"`
+	News                       *string    `json:"news,omitempty"`
+	NewsAdded                  *time.Time `json:"news_added,omitempty"`
+	GoalsScored                int        `json:"goals_scored"`
+	Assists                    int        `json:"assists"`
+	CleanSheets                int        `json:"clean_sheets"`
+	GoalsConceded              int        `json:"goals_conceded"`
+	ExpectedGoals              string     `json:"expected_goals"`
+	ExpectedAssists            string     `json:"expected_assists"`
+	ExpectedGoalInvolvements   string     `json:"expected_goal_involvements"`
+	ExpectedGoalsConceded      string     `json:"expected_goals_conceded"`
+	YellowCards                int        `json:"yellow_cards"`
+	RedCards                   int        `json:"red_cards"`
+	DefensiveContributionPer90 float64    `json:"defensive_contribution_per_90"`
+	StartsPer90                float64    `json:"starts_per_90"`
+	Minutes                    int        `json:"minutes"`
+	UpdatedAt                  time.Time  `json:"updated_at"`
+}
+
+type Elements []*Element
+
+type Event struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	DeadlineTime time.Time `json:"deadline_time"`
+	Finished     bool      `json:"finished"`
+	DataChecked  bool      `json:"data_checked"`
+	IsPrevious   bool      `json:"is_previous"`
+	IsCurrent    bool      `json:"is_current"`
+	IsNext       bool      `json:"is_next"`
+}
+
+type Events []Event
+
+type BootstrapData struct {
+	Events       *Events         `json:"events,omitempty"`
+	Chips        *Chips          `json:"chips,omitempty"`
+	TotalPlayers int             `json:"total_players,omitempty"`
+	Teams        *BootstrapTeams `json:"teams,omitempty"`
+	Elements     *Elements       `json:"elements,omitempty"`
+}
+
+type ValuableTeam struct {
+	EntryID   int    `json:"entry"`
+	Name      string `json:"name"`
+	Player    string `json:"player_name"`
+	Value     int    `json:"value_with_bank"`
+	Transfers int    `json:"total_transfers"`
+}
+
+type Entry struct {
+	ID                    int    `json:"id"`
+	Name                  string `json:"name"`
+	PlayerFirstName       string `json:"player_first_name"`
+	PlayerLastName        string `json:"player_last_name"`
+	SummaryOverallPoints  int    `json:"summary_overall_points"`
+	SummaryOverallRank    int    `json:"summary_overall_rank"`
+	SummaryEventPoints    int    `json:"summary_event_points"`
+	CurrentEvent          int    `json:"current_event"`
+	LastDeadlineValue     int    `json:"last_deadline_value"`
+	LastDeadlineBank      int    `json:"last_deadline_bank"`
+	LastDeadlineTransfers int    `json:"last_deadline_total_transfers"`
+}
+
+type League struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type LeagueEntry struct {
+	ID         int    `json:"id"`
+	Entry      int    `json:"entry"`
+	EntryName  string `json:"entry_name"`
+	PlayerName string `json:"player_name"`
+	Rank       int    `json:"rank"`
+	Total      int    `json:"total"`
+	EventTotal int    `json:"event_total"`
+}
+
+type Standings struct {
+	HasNext bool           `json:"has_next"`
+	Page    int            `json:"page"`
+	Results []*LeagueEntry `json:"results"`
+}
+
+type LeagueStandings struct {
+	League    League    `json:"league"`
+	Standings Standings `json:"standings"`
+}
+
+type Status struct {
+	BonusAdded bool   `json:"bonus_added"`
+	Date       string `json:"date"`
+	Event      int    `json:"event"`
+	Points     string `json:"points"`
+}
+
+type GameweekStatus struct {
+	Status  []Status `json:"status"`
+	Leagues string   `json:"leagues"`
+}
+
+type Pick struct {
+	Element       int  `json:"element"`
+	Position      int  `json:"position"`
+	Multiplier    int  `json:"multiplier"`
+	IsCaptain     bool `json:"is_captain"`
+	IsViceCaptain bool `json:"is_vice_captain"`
+	ElementType   int  `json:"element_type"`
+}
+
+type AutomaticSub struct {
+	Entry      int `json:"entry"`
+	ElementIn  int `json:"element_in"`
+	ElementOut int `json:"element_out"`
+	Event      int `json:"event"`
+}
+
+type EntryHistory struct {
+	Event              int `json:"event"`
+	Points             int `json:"points"`
+	TotalPoints        int `json:"total_points"`
+	Rank               int `json:"rank"`
+	OverallRank        int `json:"overall_rank"`
+	Bank               int `json:"bank"`
+	Value              int `json:"value"`
+	EventTransfers     int `json:"event_transfers"`
+	EventTransfersCost int `json:"event_transfers_cost"`
+	PointsOnBench      int `json:"points_on_bench"`
+}
+
+type EntryPicks struct {
+	ActiveChip    *string        `json:"active_chip"`
+	AutomaticSubs []AutomaticSub `json:"automatic_subs"`
+	EntryHistory  EntryHistory   `json:"entry_history"`
+	Picks         []Pick         `json:"picks"`
+}
+
+type LiveStats struct {
+	Minutes     int  `json:"minutes"`
+	TotalPoints int  `json:"total_points"`
+	Bonus       int  `json:"bonus"`
+	InDreamteam bool `json:"in_dreamteam"`
+}
+
+type LiveExplain struct {
+	Fixture int `json:"fixture"`
+}
+
+type LiveElement struct {
+	ID      int           `json:"id"`
+	Stats   LiveStats     `json:"stats"`
+	Explain []LiveExplain `json:"explain"`
+}
+
+type EventLive struct {
+	Elements []LiveElement `json:"elements"`
+}
+
+type Fixture struct {
+	ID                  int        `json:"id"`
+	Event               int        `json:"event"`
+	KickoffTime         *time.Time `json:"kickoff_time"`
+	Started             bool       `json:"started"`
+	Finished            bool       `json:"finished"`
+	FinishedProvisional bool       `json:"finished_provisional"`
+	TeamH               int        `json:"team_h"`
+	TeamA               int        `json:"team_a"`
+}
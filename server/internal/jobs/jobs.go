@@ -0,0 +1,293 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+	"github.com/divin3circle/fplduel/server/internal/scoring"
+	"github.com/divin3circle/fplduel/server/internal/services"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+	"github.com/divin3circle/fplduel/server/internal/utils"
+)
+
+const (
+	RefreshPlayersJobName  = "refresh-players"
+	RefreshTeamsJobName    = "refresh-teams"
+	CreateMatchupsJobName  = "create-matchups"
+	DeployContractsJobName = "deploy-contracts"
+	LockMatchupsJobName    = "lock-matchups"
+	ProcessRefundsJobName  = "process-refunds"
+	ReconcileBetsJobName   = "reconcile-bet-outcomes"
+	IndexEventsJobName     = "index-contract-events"
+	LiveScoresJobName      = "live-scores"
+	SettleGameweekJobName  = "settle-gameweek"
+)
+
+func RefreshPlayers(fplClient *fpl.Client, playerStore stores.PlayerStore) *Job {
+	return &Job{
+		Name:     RefreshPlayersJobName,
+		Interval: 6 * time.Hour,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			players, err := utils.GetAllPlayers(ctx, fplClient)
+			if err != nil {
+				return err
+			}
+			if err := playerStore.UpdatePlayers(players); err != nil {
+				return err
+			}
+			run.Message = fmt.Sprintf("updated %d players", len(players))
+			return nil
+		},
+	}
+}
+
+func RefreshTeams(fplClient *fpl.Client, teamStore stores.TeamStore) *Job {
+	return &Job{
+		Name:     RefreshTeamsJobName,
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			teams, err := utils.GetAllTeams(ctx, fplClient)
+			if err != nil {
+				return err
+			}
+			if err := teamStore.UpdateTeams(teams); err != nil {
+				return err
+			}
+			run.Message = fmt.Sprintf("updated %d teams", len(teams))
+			return nil
+		},
+	}
+}
+
+// CreateMatchups creates the upcoming gameweek's slate as soon as it has none.
+func CreateMatchups(matchupService *services.MatchupService) *Job {
+	return &Job{
+		Name:     CreateMatchupsJobName,
+		Interval: 15 * time.Minute,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			slate, err := matchupService.CreateSlate(ctx, services.SlateOptions{})
+			if slate != nil {
+				run.Gameweek = &slate.Gameweek
+			}
+			if errors.Is(err, stores.ErrSlateExists) || errors.Is(err, services.ErrBettingClosed) {
+				return ErrSkipped
+			}
+			if err != nil {
+				return err
+			}
+			run.Message = fmt.Sprintf("created slate %s with %d matchups", slate.ID, len(slate.Matchups))
+			return nil
+		},
+	}
+}
+
+// DeployContracts works through the contract deployment outbox.
+func DeployContracts(matchupService *services.MatchupService) *Job {
+	return &Job{
+		Name:     DeployContractsJobName,
+		Interval: 30 * time.Second,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			deployed, failed, err := matchupService.DeployPendingContracts(ctx, 10)
+			if err != nil {
+				return err
+			}
+			if deployed == 0 && failed == 0 {
+				return ErrSkipped
+			}
+			run.Message = fmt.Sprintf("deployed %d contracts, %d attempts failed", deployed, failed)
+			return nil
+		},
+	}
+}
+
+// LockMatchups closes open matchups whose betting end has passed, backfilling the betting end
+// of older matchups from their gameweek deadline.
+func LockMatchups(matchupService *services.MatchupService) *Job {
+	return &Job{
+		Name:     LockMatchupsJobName,
+		Interval: time.Minute,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			locked, err := matchupService.LockExpiredMatchups(ctx)
+			if locked > 0 {
+				run.Message = fmt.Sprintf("locked %d matchups", locked)
+			}
+			if err != nil {
+				return err
+			}
+			if locked == 0 {
+				return ErrSkipped
+			}
+			return nil
+		},
+	}
+}
+
+// ProcessRefunds voids the contracts of voided matchups and pays out their refunds.
+func ProcessRefunds(refundService *services.RefundService) *Job {
+	return &Job{
+		Name:     ProcessRefundsJobName,
+		Interval: time.Minute,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			result, err := refundService.ProcessRefunds(ctx, 50)
+			if result != nil {
+				run.Message = fmt.Sprintf("voided %d contracts, refunded %d bettors, %d refunds failed", result.Contracts, result.Refunded, result.Failed)
+			}
+			if err != nil {
+				return err
+			}
+			if result.Contracts+result.Refunded+result.Failed == 0 {
+				return ErrSkipped
+			}
+			return nil
+		},
+	}
+}
+
+// ReconcileBetOutcomes checks stored bet outcomes against the chain.
+func ReconcileBetOutcomes(reconciler *services.BetReconciler) *Job {
+	return &Job{
+		Name:     ReconcileBetsJobName,
+		Interval: 10 * time.Minute,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			result, err := reconciler.ReconcileOutcomes(ctx, 500)
+			if result != nil {
+				run.Message = fmt.Sprintf("checked %d bets, corrected %d, %d without an event", result.Checked, result.Corrected, result.Missing)
+			}
+			if err != nil {
+				return err
+			}
+			if result.Checked == 0 && result.Missing == 0 {
+				return ErrSkipped
+			}
+			return nil
+		},
+	}
+}
+
+// IndexContractEvents ingests new events from every deployed matchup contract.
+func IndexContractEvents(indexer *services.EventIndexer) *Job {
+	return &Job{
+		Name:     IndexEventsJobName,
+		Interval: time.Minute,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			result, err := indexer.IndexContracts(ctx)
+			if result != nil {
+				run.Message = fmt.Sprintf("indexed %d contracts: %d bets, %d settlements, %d claims, %d voids, %d refunds",
+					result.Contracts, result.Bets, result.Settlements, result.Claims, result.Voids, result.Refunds)
+			}
+			if err != nil {
+				return err
+			}
+			if result.Bets+result.Settlements+result.Claims+result.Voids+result.Refunds == 0 {
+				return ErrSkipped
+			}
+			return nil
+		},
+	}
+}
+
+// LiveScores rescores the current gameweek while its matches are being played.
+func LiveScores(fplClient *fpl.Client, engine *scoring.Engine) *Job {
+	return &Job{
+		Name:     LiveScoresJobName,
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			status, err := fplClient.GetEventStatus(ctx)
+			if err != nil {
+				return err
+			}
+			if !inProgress(status) {
+				return ErrSkipped
+			}
+			gameweek := status.Status[0].Event
+			run.Gameweek = &gameweek
+
+			matchups, err := engine.ScoreGameweek(ctx, gameweek)
+			if err != nil {
+				return err
+			}
+			run.Message = fmt.Sprintf("scored %d matchups", len(matchups))
+			return nil
+		},
+	}
+}
+
+// SettleGameweek computes final scores once FPL has added bonus points for every
+// matchday of the current gameweek, then settles the matchup contracts. Matchups that fail
+// to score are left unsettled and reported, while the rest settle. It runs until it has
+// succeeded once for the gameweek.
+func SettleGameweek(fplClient *fpl.Client, engine *scoring.Engine, settlementService *services.SettlementService, jobRunStore stores.JobRunStore) *Job {
+	return &Job{
+		Name:     SettleGameweekJobName,
+		Interval: 15 * time.Minute,
+		Run: func(ctx context.Context, run *stores.JobRun) error {
+			status, err := fplClient.GetEventStatus(ctx)
+			if err != nil {
+				return err
+			}
+			if !bonusAdded(status) {
+				return ErrSkipped
+			}
+			gameweek := status.Status[0].Event
+			run.Gameweek = &gameweek
+
+			done, err := jobRunStore.HasSucceeded(SettleGameweekJobName, gameweek)
+			if err != nil {
+				return err
+			}
+			if done {
+				return ErrSkipped
+			}
+
+			matchups, scoreErr := engine.ScoreGameweek(ctx, gameweek)
+			var matchupErr *scoring.MatchupError
+			if scoreErr != nil && !errors.As(scoreErr, &matchupErr) {
+				return scoreErr
+			}
+			failed := map[string]bool{}
+			var failedIDs []string
+			for _, matchupErr := range scoring.MatchupErrors(scoreErr) {
+				failed[matchupErr.MatchupID] = true
+				failedIDs = append(failedIDs, matchupErr.MatchupID)
+			}
+
+			settled, settleErr := settlementService.SettleGameweekExcept(ctx, gameweek, failed)
+			run.Message = fmt.Sprintf("final scores for %d matchups, settled %d", len(matchups)-len(failed), settled)
+			if len(failed) > 0 {
+				run.Message += fmt.Sprintf(", %d failed to score: %s", len(failed), strings.Join(failedIDs, ", "))
+				scoreErr = fmt.Errorf("%d matchups failed to score: %w", len(failed), scoreErr)
+			}
+			return errors.Join(scoreErr, settleErr)
+		},
+	}
+}
+
+// inProgress is true once any matchday has points and bonus is still outstanding.
+func inProgress(status *fpl.GameweekStatus) bool {
+	if len(status.Status) == 0 {
+		return false
+	}
+	started := false
+	for _, day := range status.Status {
+		if day.Points != "" {
+			started = true
+		}
+	}
+	return started && !bonusAdded(status)
+}
+
+func bonusAdded(status *fpl.GameweekStatus) bool {
+	if len(status.Status) == 0 {
+		return false
+	}
+	for _, day := range status.Status {
+		if !day.BonusAdded {
+			return false
+		}
+	}
+	return true
+}
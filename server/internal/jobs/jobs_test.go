@@ -0,0 +1,191 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+	"github.com/divin3circle/fplduel/server/internal/scoring"
+	"github.com/divin3circle/fplduel/server/internal/services"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// gameweekStore holds one gameweek's matchups in memory, advancing, scoring and settling them
+// the way PostgresMatchupStore does. Methods the settle job does not use are left nil.
+type gameweekStore struct {
+	stores.MatchupStore
+	matchups []*stores.Matchup
+}
+
+func (s *gameweekStore) GetGameweekMatchups(gameweek int) ([]*stores.Matchup, error) {
+	var matchups []*stores.Matchup
+	for _, m := range s.matchups {
+		if m.Gameweek == gameweek {
+			copied := *m
+			matchups = append(matchups, &copied)
+		}
+	}
+	return matchups, nil
+}
+
+func (s *gameweekStore) get(id string) *stores.Matchup {
+	for _, m := range s.matchups {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *gameweekStore) AdvanceMatchup(matchup *stores.Matchup, status, reason string) error {
+	s.get(matchup.ID).Status = status
+	matchup.Status = status
+	return nil
+}
+
+func (s *gameweekStore) UpdateMatchup(homeTeamScore, awayTeamScore int, matchup *stores.Matchup) error {
+	stored := s.get(matchup.ID)
+	stored.HomeTeamScore, stored.AwayTeamScore = homeTeamScore, awayTeamScore
+	return nil
+}
+
+func (s *gameweekStore) MarkSettled(matchup *stores.Matchup, winner stores.Outcome, txHash string, settledAt time.Time) error {
+	stored := s.get(matchup.ID)
+	stored.Status = stores.MatchupSettled
+	stored.Winner = &winner
+	stored.SettledAt = &settledAt
+	return nil
+}
+
+type elementTypes struct {
+	stores.PlayerStore
+}
+
+// GetElementTypes places elements 1 and 12 in goal, 2-5 and 13 in defence, 6-9 and 14 in
+// midfield and the rest up front.
+func (elementTypes) GetElementTypes(ids []int) (map[int]int, error) {
+	types := make(map[int]int, len(ids))
+	for _, id := range ids {
+		switch {
+		case id == 1 || id == 12:
+			types[id] = scoring.Goalkeeper
+		case id <= 5 || id == 13:
+			types[id] = scoring.Defender
+		case id <= 9 || id == 14:
+			types[id] = scoring.Midfielder
+		default:
+			types[id] = scoring.Forward
+		}
+	}
+	return types, nil
+}
+
+type noRunsYet struct {
+	stores.JobRunStore
+}
+
+func (noRunsYet) HasSucceeded(jobName string, gameweek int) (bool, error) { return false, nil }
+
+// finishedGameweek serves FPL for a gameweek 7 whose bonus is in. Every entry picked elements
+// 1-15, each scoring its ID in points, and entry N captained element N, so entry N scores
+// 66 + N. The picks of entry 404 cannot be found.
+func finishedGameweek(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/event-status/":
+			fmt.Fprint(w, `{"status": [{"event": 7, "bonus_added": true, "points": "r"}]}`)
+		case "/bootstrap-static/":
+			fmt.Fprint(w, `{"events": [{"id": 7}]}`)
+		case "/fixtures/":
+			fmt.Fprint(w, `[{"id": 1, "event": 7, "finished": true}]`)
+		case "/event/7/live/":
+			elements := make([]string, 15)
+			for i := range elements {
+				elements[i] = fmt.Sprintf(`{"id": %d, "stats": {"minutes": 90, "total_points": %d}, "explain": [{"fixture": 1}]}`, i+1, i+1)
+			}
+			fmt.Fprintf(w, `{"elements": [%s]}`, strings.Join(elements, ","))
+		default:
+			var entry int
+			if _, err := fmt.Sscanf(r.URL.Path, "/entry/%d/event/7/picks/", &entry); err != nil || entry == 404 {
+				http.NotFound(w, r)
+				return
+			}
+			picks := make([]string, 15)
+			for i := range picks {
+				picks[i] = fmt.Sprintf(`{"element": %d, "position": %d, "multiplier": 1, "is_captain": %t}`, i+1, i+1, i+1 == entry)
+			}
+			fmt.Fprintf(w, `{"picks": [%s], "entry_history": {"event": 7}}`, strings.Join(picks, ","))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// One entry that cannot be scored must not hold back settlement of the rest of the gameweek.
+func TestSettleGameweekSettlesScoredMatchups(t *testing.T) {
+	server := finishedGameweek(t)
+	fplClient := fpl.NewClient(server.URL, server.Client())
+
+	closed := time.Now().Add(-time.Hour)
+	matchup := func(id string, home, away int) *stores.Matchup {
+		return &stores.Matchup{
+			ID:               id,
+			Gameweek:         7,
+			Status:           stores.MatchupLocked,
+			HomeTeamID:       home,
+			AwayTeamID:       away,
+			BettingEndsAt:    &closed,
+			DeploymentStatus: stores.DeploymentDeployed,
+			ContractAddress:  "0x" + strings.Repeat("0", 38) + id,
+		}
+	}
+	store := &gameweekStore{matchups: []*stores.Matchup{
+		matchup("m1", 10, 1),
+		matchup("m2", 2, 404),
+		matchup("m3", 3, 4),
+	}}
+	logger := log.New(io.Discard, "", 0)
+	engine := scoring.NewEngine(logger, fplClient, store, elementTypes{})
+	settler := contracts.NewFakeSettler()
+	settlementService := services.NewSettlementService(logger, settler, store)
+
+	run := &stores.JobRun{}
+	err := SettleGameweek(fplClient, engine, settlementService, noRunsYet{}).Run(context.Background(), run)
+	var matchupErr *scoring.MatchupError
+	if !errors.As(err, &matchupErr) || matchupErr.MatchupID != "m2" {
+		t.Fatalf("Run() = %v, want matchup m2 reported", err)
+	}
+	if !strings.Contains(run.Message, "settled 2") || !strings.Contains(run.Message, "m2") {
+		t.Errorf("message = %q, want 2 settled and m2 reported", run.Message)
+	}
+
+	tests := []struct {
+		id         string
+		wantStatus string
+		wantWinner stores.Outcome
+	}{
+		{id: "m1", wantStatus: stores.MatchupSettled, wantWinner: stores.OutcomeHome},
+		{id: "m2", wantStatus: stores.MatchupLocked},
+		{id: "m3", wantStatus: stores.MatchupSettled, wantWinner: stores.OutcomeAway},
+	}
+	for _, tt := range tests {
+		m := store.get(tt.id)
+		if m.Status != tt.wantStatus {
+			t.Errorf("matchup %s is %s, want %s", tt.id, m.Status, tt.wantStatus)
+		}
+		winner, settled := settler.Winner(m.ContractAddress)
+		if settled != (tt.wantStatus == stores.MatchupSettled) || (settled && winner != tt.wantWinner) {
+			t.Errorf("matchup %s contract settled = %t with %d, want %t with %d", tt.id, settled, winner, tt.wantStatus == stores.MatchupSettled, tt.wantWinner)
+		}
+	}
+}
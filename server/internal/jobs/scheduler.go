@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+const DefaultTimeout = 10 * time.Minute
+
+// ErrSkipped is returned by a job that found nothing to do; the run is marked skipped and
+// not kept in job_runs.
+var ErrSkipped = errors.New("nothing to do")
+
+// Func does one run of a job. It may fill in run.Gameweek and run.Message.
+type Func func(ctx context.Context, run *stores.JobRun) error
+
+type Job struct {
+	Name     string        `json:"name"`
+	Interval time.Duration `json:"interval"`
+	Timeout  time.Duration `json:"timeout"`
+	Run      Func          `json:"-"`
+}
+
+// Scheduler runs each registered job once at start and then every Interval, recording
+// every run that did something in job_runs. Runs of the same job never overlap.
+type Scheduler struct {
+	Logger      *log.Logger
+	JobRunStore stores.JobRunStore
+
+	mu     sync.Mutex
+	jobs   []*Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(logger *log.Logger, jobRunStore stores.JobRunStore) *Scheduler {
+	return &Scheduler{
+		Logger:      logger,
+		JobRunStore: jobRunStore,
+	}
+}
+
+func (s *Scheduler) Register(job *Job) {
+	if job.Timeout == 0 {
+		job.Timeout = DefaultTimeout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *Scheduler) Jobs() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Job(nil), s.jobs...)
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancel = cancel
+	jobs := append([]*Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	s.Logger.Printf("Scheduler started with %d jobs", len(jobs))
+}
+
+// Stop cancels running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job *Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunNow(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunNow runs a job immediately and records the outcome. A skipped run is discarded.
+func (s *Scheduler) RunNow(ctx context.Context, job *Job) *stores.JobRun {
+	run := &stores.JobRun{JobName: job.Name}
+	if err := s.JobRunStore.StartJobRun(run); err != nil {
+		s.Logger.Printf("Error recording start of job %s: %v", job.Name, err)
+		return nil
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	err := s.safeRun(runCtx, job, run)
+	cancel()
+
+	switch {
+	case errors.Is(err, ErrSkipped):
+		run.Status = stores.JobRunSkipped
+		if err := s.JobRunStore.DiscardJobRun(run); err != nil {
+			s.Logger.Printf("Error discarding skipped run of job %s: %v", job.Name, err)
+		}
+		return run
+	case err != nil:
+		run.Status = stores.JobRunFailed
+		run.Error = err.Error()
+		s.Logger.Printf("Job %s failed: %v", job.Name, err)
+	default:
+		run.Status = stores.JobRunSucceeded
+		s.Logger.Printf("Job %s succeeded: %s", job.Name, run.Message)
+	}
+
+	if err := s.JobRunStore.FinishJobRun(run); err != nil {
+		s.Logger.Printf("Error recording end of job %s: %v", job.Name, err)
+	}
+	return run
+}
+
+func (s *Scheduler) safeRun(ctx context.Context, job *Job, run *stores.JobRun) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx, run)
+}
@@ -0,0 +1,204 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+)
+
+const (
+	DefaultBaseURL = "https://testnet.mirrornode.hedera.com"
+	DefaultTimeout = time.Minute
+	// DefaultRequestsPerSecond stays below the public mirror node's per-IP limit.
+	DefaultRequestsPerSecond = 20
+	DefaultBurst             = 40
+	pageLimit                = 100
+)
+
+// ErrNotFound is returned when the mirror node has no record of the requested entity. Recent
+// transactions take a few seconds to reach the mirror node, so callers may want to retry.
+var ErrNotFound = errors.New("mirror: not found")
+
+// StatusError is returned when the mirror node answers with a non-200 status.
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("mirror: %s returned status %d", e.URL, e.StatusCode)
+}
+
+// Client reads from the Hedera mirror node REST API. It retries transient failures with the
+// same transport as the FPL client.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: fpl.NewRetryTransport(nil, fpl.NewRateLimiter(DefaultRequestsPerSecond, DefaultBurst)),
+		}
+	}
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: httpClient,
+	}
+}
+
+type Links struct {
+	Next *string `json:"next"`
+}
+
+// ContractLog is one EVM log emitted by a contract.
+type ContractLog struct {
+	Address          string   `json:"address"`
+	ContractID       string   `json:"contract_id"`
+	Data             string   `json:"data"`
+	Index            int      `json:"index"`
+	Topics           []string `json:"topics"`
+	BlockNumber      int64    `json:"block_number"`
+	Timestamp        string   `json:"timestamp"`
+	TransactionHash  string   `json:"transaction_hash"`
+	TransactionIndex int      `json:"transaction_index"`
+}
+
+type contractLogsPage struct {
+	Logs  []*ContractLog `json:"logs"`
+	Links Links          `json:"links"`
+}
+
+// ContractResult is the outcome of a contract call or creation. Amount is in tinybars and
+// FunctionParameters is the hex calldata the contract was called with.
+type ContractResult struct {
+	Address            string `json:"address"`
+	Amount             int64  `json:"amount"`
+	ContractID         string `json:"contract_id"`
+	ErrorMessage       string `json:"error_message"`
+	From               string `json:"from"`
+	FunctionParameters string `json:"function_parameters"`
+	GasUsed            int64  `json:"gas_used"`
+	Hash               string `json:"hash"`
+	Result             string `json:"result"`
+	Status             string `json:"status"`
+	Timestamp          string `json:"timestamp"`
+	To                 string `json:"to"`
+}
+
+// ResultSuccess is the Result of a contract call that executed without reverting.
+const ResultSuccess = "SUCCESS"
+
+// TransactionID converts a transaction ID from the SDK's "0.0.1234@1700000000.123456789" form
+// to the "0.0.1234-1700000000-123456789" form the REST API expects. IDs already in that form
+// and hashes are returned unchanged.
+func TransactionID(id string) string {
+	account, validStart, ok := strings.Cut(id, "@")
+	if !ok {
+		return id
+	}
+	return account + "-" + strings.Replace(validStart, ".", "-", 1)
+}
+
+// ParseTimestamp parses a consensus timestamp in the mirror node's "seconds.nanoseconds" form.
+func ParseTimestamp(timestamp string) (time.Time, error) {
+	seconds, nanos, _ := strings.Cut(timestamp, ".")
+	s, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid consensus timestamp %q", timestamp)
+	}
+	n, err := strconv.ParseInt((nanos + "000000000")[:9], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid consensus timestamp %q", timestamp)
+	}
+	return time.Unix(s, n).UTC(), nil
+}
+
+// GetContractResult returns the contract result of a transaction, looked up by its
+// Ethereum-style hash or its Hedera transaction ID. It returns ErrNotFound if the mirror
+// node has no such transaction yet.
+func (c *Client) GetContractResult(ctx context.Context, transaction string) (*ContractResult, error) {
+	var result ContractResult
+	err := c.getJSON(ctx, "/api/v1/contracts/results/"+url.PathEscape(transaction), &result)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LogFilter narrows GetContractLogs. Zero fields match everything.
+type LogFilter struct {
+	// Topic0 matches logs whose first topic, the event signature hash, is Topic0.
+	Topic0 string
+	// FromTimestamp matches logs at or after a consensus timestamp ("seconds.nanoseconds").
+	FromTimestamp string
+}
+
+// GetContractLogs returns the logs of a contract that match filter, in consensus order.
+func (c *Client) GetContractLogs(ctx context.Context, contract string, filter LogFilter) ([]*ContractLog, error) {
+	query := url.Values{}
+	query.Set("order", "asc")
+	query.Set("limit", fmt.Sprint(pageLimit))
+	if filter.Topic0 != "" {
+		query.Set("topic0", filter.Topic0)
+	}
+	if filter.FromTimestamp != "" {
+		query.Set("timestamp", "gte:"+filter.FromTimestamp)
+	}
+	path := fmt.Sprintf("/api/v1/contracts/%s/results/logs?%s", contract, query.Encode())
+
+	var logs []*ContractLog
+	for path != "" {
+		var page contractLogsPage
+		if err := c.getJSON(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		logs = append(logs, page.Logs...)
+		path = ""
+		if page.Links.Next != nil {
+			path = *page.Links.Next
+		}
+	}
+	return logs, nil
+}
+
+// getJSON fetches a path relative to BaseURL, which is also the form of links.next.
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	url := c.BaseURL + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode, URL: url}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("mirror: failed to decode %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package mirror
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// StandIn is an in-memory stand-in for the mirror node REST API. It serves the endpoints used
+// by Client from records added with AddContractResult and AddLog, so bet verification and log
+// indexing can run locally without a network:
+//
+//	standIn := mirror.NewStandIn()
+//	server := httptest.NewServer(standIn)
+//	client := mirror.NewClient(server.URL, server.Client())
+type StandIn struct {
+	mu      sync.Mutex
+	results map[string]*ContractResult
+	aliases map[string]string
+	logs    map[string][]*ContractLog
+}
+
+func NewStandIn() *StandIn {
+	return &StandIn{
+		results: make(map[string]*ContractResult),
+		aliases: make(map[string]string),
+		logs:    make(map[string][]*ContractLog),
+	}
+}
+
+// AddContractResult records a transaction's contract result under its hash.
+func (s *StandIn) AddContractResult(result *ContractResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[strings.ToLower(result.Hash)] = result
+}
+
+// AddTransactionID makes the contract result stored under hash also answer to a Hedera
+// transaction ID, in either the SDK or the REST API form.
+func (s *StandIn) AddTransactionID(transactionID, hash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aliases[TransactionID(transactionID)] = strings.ToLower(hash)
+}
+
+// AddLog appends a log to the contract at the log's Address.
+func (s *StandIn) AddLog(log *ContractLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	contract := strings.ToLower(log.Address)
+	s.logs[contract] = append(s.logs[contract], log)
+}
+
+func (s *StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/contracts/")
+	switch {
+	case strings.HasPrefix(path, "results/"):
+		s.serveContractResult(w, strings.TrimPrefix(path, "results/"))
+	case strings.HasSuffix(path, "/results/logs"):
+		query := r.URL.Query()
+		filter := LogFilter{
+			Topic0:        query.Get("topic0"),
+			FromTimestamp: strings.TrimPrefix(query.Get("timestamp"), "gte:"),
+		}
+		s.serveContractLogs(w, strings.TrimSuffix(path, "/results/logs"), filter)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *StandIn) serveContractResult(w http.ResponseWriter, transaction string) {
+	s.mu.Lock()
+	key := strings.ToLower(transaction)
+	if hash, ok := s.aliases[transaction]; ok {
+		key = hash
+	}
+	result, ok := s.results[key]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// serveContractLogs returns every matching log in one page, so links.next is always null.
+func (s *StandIn) serveContractLogs(w http.ResponseWriter, contract string, filter LogFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page := contractLogsPage{Logs: []*ContractLog{}}
+	for _, log := range s.logs[strings.ToLower(contract)] {
+		if filter.Topic0 != "" && (len(log.Topics) == 0 || !strings.EqualFold(log.Topics[0], filter.Topic0)) {
+			continue
+		}
+		if filter.FromTimestamp != "" && timestampBefore(log.Timestamp, filter.FromTimestamp) {
+			continue
+		}
+		page.Logs = append(page.Logs, log)
+	}
+	writeJSON(w, page)
+}
+
+// timestampBefore compares two "seconds.nanoseconds" consensus timestamps.
+func timestampBefore(a, b string) bool {
+	aSeconds, aNanos := splitTimestamp(a)
+	bSeconds, bNanos := splitTimestamp(b)
+	if aSeconds != bSeconds {
+		return aSeconds < bSeconds
+	}
+	return aNanos < bNanos
+}
+
+func splitTimestamp(timestamp string) (int64, int64) {
+	seconds, nanos, _ := strings.Cut(timestamp, ".")
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+	n, _ := strconv.ParseInt((nanos + "000000000")[:9], 10, 64)
+	return s, n
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,140 @@
+// Package pricing seeds the virtual pools that set a matchup contract's opening odds.
+package pricing
+
+import (
+	"math"
+)
+
+const (
+	// FixedModelVersion is the flat 100/50/100 HBAR seeding used before the strength model,
+	// recorded on matchups created before it.
+	FixedModelVersion = "fixed-v0"
+	// StrengthModelVersion is StrengthModel with its current inputs and weights.
+	StrengthModelVersion = "strength-v1"
+)
+
+// TeamForm is what the model knows about one side of a matchup. Value is in tenths of a
+// million, as FPL reports it. A zero OverallRank means the entry is unranked.
+type TeamForm struct {
+	Value       int `json:"value"`
+	OverallRank int `json:"overall_rank"`
+	EventPoints int `json:"event_points"`
+	Transfers   int `json:"transfers"`
+}
+
+// Inputs are everything a matchup was priced from, stored with it for auditing.
+type Inputs struct {
+	Home TeamForm `json:"home"`
+	Away TeamForm `json:"away"`
+}
+
+// Seeds are the virtual pools, in tinybars, and the probabilities they encode.
+type Seeds struct {
+	Home         int64  `json:"home"`
+	Draw         int64  `json:"draw"`
+	Away         int64  `json:"away"`
+	ModelVersion string `json:"model_version"`
+
+	HomeProbability float64 `json:"home_probability"`
+	DrawProbability float64 `json:"draw_probability"`
+	AwayProbability float64 `json:"away_probability"`
+}
+
+// Model prices matchups. Version is stored with every matchup it priced.
+type Model interface {
+	Version() string
+	Seed(inputs Inputs) Seeds
+}
+
+// StrengthModel rates each side from its form and turns the difference into win, draw and
+// loss probabilities. The virtual pools split Liquidity by those probabilities, so the
+// opening odds of each outcome are the inverse of its probability.
+//
+// Value is weighted per £1m, rank per factor of ten, points per gameweek point and
+// transfers per transfer made; a positive rating favours the home side. The draw takes
+// DrawShare when the sides are level and less the more one side is favoured, never less
+// than MinDrawShare.
+type StrengthModel struct {
+	Liquidity       int64
+	DrawShare       float64
+	MinDrawShare    float64
+	ValueWeight     float64
+	RankWeight      float64
+	PointsWeight    float64
+	TransfersWeight float64
+	// MinPool keeps every outcome priced; a zero pool would make its odds undefined.
+	MinPool int64
+}
+
+const tinybarsPerHbar = 100_000_000
+
+// DefaultStrengthModel keeps the 250 HBAR of liquidity and the 20% draw share of the old
+// 100/50/100 seeding for evenly matched sides.
+func DefaultStrengthModel() *StrengthModel {
+	return &StrengthModel{
+		Liquidity:       250 * tinybarsPerHbar,
+		DrawShare:       0.2,
+		MinDrawShare:    0.05,
+		ValueWeight:     0.08,
+		RankWeight:      0.2,
+		PointsWeight:    0.01,
+		TransfersWeight: -0.005,
+		MinPool:         1 * tinybarsPerHbar,
+	}
+}
+
+func (m *StrengthModel) Version() string { return StrengthModelVersion }
+
+// Seed prices a matchup.
+func (m *StrengthModel) Seed(inputs Inputs) Seeds {
+	rating := m.rating(inputs.Home) - m.rating(inputs.Away)
+	rating += m.rankRating(inputs.Home.OverallRank, inputs.Away.OverallRank)
+
+	// probability the home side finishes ahead, given there is no draw
+	home := 1 / (1 + math.Exp(-rating))
+	draw := math.Max(m.MinDrawShare, m.DrawShare*(1-math.Abs(2*home-1)))
+
+	seeds := Seeds{
+		ModelVersion:    m.Version(),
+		HomeProbability: home * (1 - draw),
+		DrawProbability: draw,
+		AwayProbability: (1 - home) * (1 - draw),
+	}
+	seeds.Home = m.pool(seeds.HomeProbability)
+	seeds.Draw = m.pool(seeds.DrawProbability)
+	seeds.Away = m.pool(seeds.AwayProbability)
+	return seeds
+}
+
+func (m *StrengthModel) rating(form TeamForm) float64 {
+	return m.ValueWeight*float64(form.Value)/10 +
+		m.PointsWeight*float64(form.EventPoints) +
+		m.TransfersWeight*float64(form.Transfers)
+}
+
+// rankRating favours the better-ranked side. Ranks only count when both sides have one.
+func (m *StrengthModel) rankRating(home, away int) float64 {
+	if home <= 0 || away <= 0 {
+		return 0
+	}
+	return m.RankWeight * (math.Log10(float64(away)) - math.Log10(float64(home)))
+}
+
+// pool rounds a share of Liquidity to whole HBAR.
+func (m *StrengthModel) pool(probability float64) int64 {
+	hbar := math.Round(probability * float64(m.Liquidity) / tinybarsPerHbar)
+	return max(int64(hbar)*tinybarsPerHbar, m.MinPool)
+}
+
+// FixedSeeds are the 100/50/100 HBAR pools every contract was seeded with before pricing.
+func FixedSeeds() Seeds {
+	return Seeds{
+		Home:            100 * tinybarsPerHbar,
+		Draw:            50 * tinybarsPerHbar,
+		Away:            100 * tinybarsPerHbar,
+		ModelVersion:    FixedModelVersion,
+		HomeProbability: 0.4,
+		DrawProbability: 0.2,
+		AwayProbability: 0.4,
+	}
+}
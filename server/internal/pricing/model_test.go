@@ -0,0 +1,105 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStrengthModelSeed(t *testing.T) {
+	m := DefaultStrengthModel()
+	even := TeamForm{Value: 1000, OverallRank: 50_000, EventPoints: 60, Transfers: 1}
+
+	tests := []struct {
+		name       string
+		inputs     Inputs
+		wantEven   bool
+		favourHome bool
+		favourAway bool
+	}{
+		{
+			name:     "evenly matched sides get the old flat pools",
+			inputs:   Inputs{Home: even, Away: even},
+			wantEven: true,
+		},
+		{
+			name: "rank ignored when one side is unranked",
+			inputs: Inputs{
+				Home: TeamForm{Value: 1000, OverallRank: 1_000, EventPoints: 60},
+				Away: TeamForm{Value: 1000, EventPoints: 60},
+			},
+			wantEven: true,
+		},
+		{
+			name: "better rank favours home",
+			inputs: Inputs{
+				Home: TeamForm{Value: 1000, OverallRank: 1_000, EventPoints: 60},
+				Away: TeamForm{Value: 1000, OverallRank: 100_000, EventPoints: 60},
+			},
+			favourHome: true,
+		},
+		{
+			name: "higher value and points favour away",
+			inputs: Inputs{
+				Home: TeamForm{Value: 1000, EventPoints: 40},
+				Away: TeamForm{Value: 1040, EventPoints: 80},
+			},
+			favourAway: true,
+		},
+		{
+			name: "transfer hits count against a side",
+			inputs: Inputs{
+				Home: TeamForm{Value: 1000, EventPoints: 60, Transfers: 40},
+				Away: TeamForm{Value: 1000, EventPoints: 60},
+			},
+			favourAway: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seeds := m.Seed(tt.inputs)
+			if seeds.ModelVersion != StrengthModelVersion {
+				t.Errorf("model version = %q, want %q", seeds.ModelVersion, StrengthModelVersion)
+			}
+			if sum := seeds.HomeProbability + seeds.DrawProbability + seeds.AwayProbability; math.Abs(sum-1) > 1e-9 {
+				t.Errorf("probabilities sum to %v, want 1", sum)
+			}
+			switch {
+			case tt.wantEven:
+				flat := FixedSeeds()
+				if seeds.Home != flat.Home || seeds.Draw != flat.Draw || seeds.Away != flat.Away {
+					t.Errorf("pools = %d/%d/%d, want %d/%d/%d", seeds.Home, seeds.Draw, seeds.Away, flat.Home, flat.Draw, flat.Away)
+				}
+			case tt.favourHome:
+				if seeds.Home <= seeds.Away {
+					t.Errorf("home pool %d not above away pool %d", seeds.Home, seeds.Away)
+				}
+			case tt.favourAway:
+				if seeds.Away <= seeds.Home {
+					t.Errorf("away pool %d not above home pool %d", seeds.Away, seeds.Home)
+				}
+			}
+			if !tt.wantEven && seeds.DrawProbability >= m.DrawShare {
+				t.Errorf("draw probability %v not below the even draw share %v", seeds.DrawProbability, m.DrawShare)
+			}
+		})
+	}
+}
+
+func TestStrengthModelSeedMismatch(t *testing.T) {
+	m := DefaultStrengthModel()
+	seeds := m.Seed(Inputs{
+		Home: TeamForm{Value: 2000, OverallRank: 1, EventPoints: 120},
+		Away: TeamForm{Value: 800, OverallRank: 5_000_000, EventPoints: 10, Transfers: 20},
+	})
+	if seeds.DrawProbability != m.MinDrawShare {
+		t.Errorf("draw probability = %v, want the floor %v", seeds.DrawProbability, m.MinDrawShare)
+	}
+	if seeds.Away != m.MinPool {
+		t.Errorf("away pool = %d, want the minimum %d", seeds.Away, m.MinPool)
+	}
+	for _, pool := range []int64{seeds.Home, seeds.Draw, seeds.Away} {
+		if pool%tinybarsPerHbar != 0 {
+			t.Errorf("pool %d is not whole HBAR", pool)
+		}
+	}
+}
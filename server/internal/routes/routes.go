@@ -24,12 +24,19 @@ func SetupRoutes(app *app.Application) *chi.Mux {
 	// MATCHUP ROUTES
 	/* POST */
 	r.Post("/matchup", app.MatchupHandler.CreateMatchups)
+	r.Post("/gameweek/{gameweek}/score", app.MatchupHandler.ScoreGameweek)
+	r.Post("/matchup/{id}/deployment/retry", app.MatchupHandler.RetryDeployment)
+	r.Post("/matchup/{id}/settle", app.MatchupHandler.SettleMatchup)
+	r.Post("/matchup/{id}/void", app.MatchupHandler.VoidMatchup)
 
 	/* PUT */
 	r.Put("/matchup/{id}/score", app.MatchupHandler.UpdateMatchupScores)
 
 	/* GET */
 	r.Get("/matchup/{id}", app.MatchupHandler.GetMatchupByID)
+	r.Get("/matchup/{id}/odds", app.MatchupHandler.GetMatchupOdds)
+	r.Get("/matchup/{id}/transitions", app.MatchupHandler.GetMatchupTransitions)
+	r.Get("/matchup/{id}/refunds", app.MatchupHandler.GetMatchupRefunds)
 	r.Get("/matchup", app.MatchupHandler.GetAllMatchups)
 	r.Get("/gameweek/{gameweek}", app.MatchupHandler.GetMatchupsByGameWeek)
 	r.Get("/gameweek", app.MatchupHandler.GetCurrentGameweek)
@@ -63,6 +70,19 @@ func SetupRoutes(app *app.Application) *chi.Mux {
 	/* GET */
 	r.Get("/bets/{address}", app.BetHandler.GetBetsByUserAddress)
 	r.Get("/bets/{matchup}/count", app.BetHandler.GetNumberOfBets)
+	r.Get("/bets/{matchup}/market", app.BetHandler.GetMarket)
+	r.Get("/bets/{address}/payouts", app.BetHandler.GetPayouts)
+	r.Get("/bets/{address}/stats", app.BetHandler.GetStats)
+	r.Get("/leaderboard", app.BetHandler.GetLeaderboard)
+
+	// ADMIN ROUTES
+	/* GET */
+	r.Get("/admin/jobs", app.JobHandler.HandleListJobRuns)
+
+	/* POST */
+	r.Post("/admin/slate/regenerate", app.MatchupHandler.RegenerateSlate)
+	r.Post("/admin/deployments/retry", app.MatchupHandler.RetryFailedDeployments)
+	r.Post("/admin/bets/reconcile", app.BetHandler.ReconcileOutcomes)
 
 	return r
 }
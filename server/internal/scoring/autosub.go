@@ -0,0 +1,125 @@
+package scoring
+
+import (
+	"sort"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+)
+
+// Player.ElementType values
+const (
+	Goalkeeper = 1
+	Defender   = 2
+	Midfielder = 3
+	Forward    = 4
+)
+
+// minimum outfield players per position in a valid starting XI
+var formationMinimums = map[int]int{
+	Defender:   3,
+	Midfielder: 2,
+	Forward:    1,
+}
+
+type Substitution struct {
+	ElementIn  int `json:"element_in"`
+	ElementOut int `json:"element_out"`
+}
+
+// GameweekData is everything needed to score picks for one gameweek.
+type GameweekData struct {
+	Live             map[int]fpl.LiveElement
+	FinishedFixtures map[int]bool
+	ElementTypes     map[int]int
+}
+
+// didNotPlay is true once a player has 0 minutes and every fixture he had is over.
+// Players with no fixture at all (blank gameweek) count as not playing.
+func (gd *GameweekData) didNotPlay(element int) bool {
+	live := gd.Live[element]
+	if live.Stats.Minutes > 0 {
+		return false
+	}
+	for _, explain := range live.Explain {
+		if !gd.FinishedFixtures[explain.Fixture] {
+			return false
+		}
+	}
+	return true
+}
+
+func (gd *GameweekData) elementType(pick fpl.Pick) int {
+	if elementType, ok := gd.ElementTypes[pick.Element]; ok {
+		return elementType
+	}
+	return pick.ElementType
+}
+
+// applyAutoSubs follows the official FPL rules: starters who did not play are replaced
+// in bench order, a goalkeeper only by the bench goalkeeper, and an outfield player only
+// by a bench player whose arrival still allows 3 DEF, 2 MID and 1 FWD among those who play.
+// It returns the final XI and the substitutions made.
+func applyAutoSubs(picks []fpl.Pick, data *GameweekData) ([]fpl.Pick, []Substitution) {
+	var starters, bench []fpl.Pick
+	for _, pick := range picks {
+		if pick.Position <= startingPlayers {
+			starters = append(starters, pick)
+		} else {
+			bench = append(bench, pick)
+		}
+	}
+	sort.Slice(starters, func(i, j int) bool { return starters[i].Position < starters[j].Position })
+	sort.Slice(bench, func(i, j int) bool { return bench[i].Position < bench[j].Position })
+
+	used := make(map[int]bool)
+	var subs []Substitution
+	for i, starter := range starters {
+		if !data.didNotPlay(starter.Element) {
+			continue
+		}
+
+		for _, sub := range bench {
+			if used[sub.Element] || data.Live[sub.Element].Stats.Minutes == 0 {
+				continue
+			}
+			isGoalkeeper := data.elementType(starter) == Goalkeeper
+			if isGoalkeeper != (data.elementType(sub) == Goalkeeper) {
+				continue
+			}
+			if !isGoalkeeper && !validAfterSwap(starters, i, sub, data) {
+				continue
+			}
+
+			used[sub.Element] = true
+			subs = append(subs, Substitution{ElementIn: sub.Element, ElementOut: starter.Element})
+			starters[i] = sub
+			break
+		}
+	}
+	return starters, subs
+}
+
+// validAfterSwap reports whether in can replace starters[out] without breaking the formation.
+// Only players who take part count: starters who played or may still play, and substitutes
+// already brought on. Starters who did not play leave open slots, so the formation only breaks
+// when the minimums could no longer be met by filling every open slot.
+func validAfterSwap(starters []fpl.Pick, out int, in fpl.Pick, data *GameweekData) bool {
+	counts := make(map[int]int)
+	outfield := 0
+	for i, pick := range starters {
+		if i == out {
+			pick = in
+		} else if data.didNotPlay(pick.Element) {
+			continue
+		}
+		if elementType := data.elementType(pick); elementType != Goalkeeper {
+			counts[elementType]++
+			outfield++
+		}
+	}
+	short := 0
+	for position, minimum := range formationMinimums {
+		short += max(minimum-counts[position], 0)
+	}
+	return short <= startingPlayers-1-outfield
+}
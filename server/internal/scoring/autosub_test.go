@@ -0,0 +1,102 @@
+package scoring
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+)
+
+// squad builds fifteen picks from element types in position order, using the position as
+// the element ID, and live data where every element played the given minutes in fixture 1.
+func squad(types [15]int, minutes map[int]int) ([]fpl.Pick, *GameweekData) {
+	data := &GameweekData{
+		Live:             make(map[int]fpl.LiveElement),
+		FinishedFixtures: map[int]bool{1: true},
+		ElementTypes:     make(map[int]int),
+	}
+	picks := make([]fpl.Pick, 0, len(types))
+	for i, elementType := range types {
+		element := i + 1
+		played, ok := minutes[element]
+		if !ok {
+			played = 90
+		}
+		picks = append(picks, fpl.Pick{Element: element, Position: element, Multiplier: 1})
+		data.ElementTypes[element] = elementType
+		data.Live[element] = fpl.LiveElement{
+			ID:      element,
+			Stats:   fpl.LiveStats{Minutes: played},
+			Explain: []fpl.LiveExplain{{Fixture: 1}},
+		}
+	}
+	return picks, data
+}
+
+func TestApplyAutoSubs(t *testing.T) {
+	const (
+		G = Goalkeeper
+		D = Defender
+		M = Midfielder
+		F = Forward
+	)
+	tests := []struct {
+		name    string
+		types   [15]int
+		minutes map[int]int
+		pending []int
+		want    []Substitution
+	}{
+		{
+			name:  "everyone played",
+			types: [15]int{G, D, D, D, D, M, M, M, M, F, F, G, D, M, F},
+		},
+		{
+			name:    "goalkeeper only replaced by bench goalkeeper",
+			types:   [15]int{G, D, D, D, D, M, M, M, M, F, F, G, D, M, F},
+			minutes: map[int]int{1: 0},
+			want:    []Substitution{{ElementIn: 12, ElementOut: 1}},
+		},
+		{
+			name:    "first bench player who played comes on",
+			types:   [15]int{G, D, D, D, D, M, M, M, M, F, F, G, D, M, F},
+			minutes: map[int]int{6: 0, 13: 0},
+			want:    []Substitution{{ElementIn: 14, ElementOut: 6}},
+		},
+		{
+			name:    "bench order skips a swap that breaks the defence",
+			types:   [15]int{G, D, D, D, M, M, M, M, F, F, F, G, M, D, F},
+			minutes: map[int]int{2: 0},
+			want:    []Substitution{{ElementIn: 14, ElementOut: 2}},
+		},
+		{
+			name:    "blank starters not counted towards the formation",
+			types:   [15]int{G, D, D, D, M, M, M, M, F, F, F, G, M, D, M},
+			minutes: map[int]int{2: 0, 5: 0},
+			want:    []Substitution{{ElementIn: 13, ElementOut: 2}, {ElementIn: 14, ElementOut: 5}},
+		},
+		{
+			name:    "defender replaces defender when two of three blank",
+			types:   [15]int{G, D, D, D, M, M, M, M, F, F, F, G, M, D, M},
+			minutes: map[int]int{2: 0, 3: 0},
+			want:    []Substitution{{ElementIn: 14, ElementOut: 2}},
+		},
+		{
+			name:    "player still to play is not replaced",
+			types:   [15]int{G, D, D, D, D, M, M, M, M, F, F, G, D, M, F},
+			pending: []int{7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picks, data := squad(tt.types, tt.minutes)
+			for _, element := range tt.pending {
+				data.Live[element] = fpl.LiveElement{ID: element, Explain: []fpl.LiveExplain{{Fixture: 2}}}
+			}
+			_, subs := applyAutoSubs(picks, data)
+			if !reflect.DeepEqual(subs, tt.want) {
+				t.Errorf("subs = %v, want %v", subs, tt.want)
+			}
+		})
+	}
+}
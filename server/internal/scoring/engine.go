@@ -0,0 +1,243 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+const (
+	ChipBenchBoost    = "bboost"
+	ChipTripleCaptain = "3xc"
+
+	startingPlayers = 11
+)
+
+// ManagerScore is one manager's gameweek total as computed from live player points.
+type ManagerScore struct {
+	EntryID      int    `json:"entry_id"`
+	Gameweek     int    `json:"gameweek"`
+	ActiveChip   string `json:"active_chip"`
+	Points       int    `json:"points"`
+	TransferCost int    `json:"transfer_cost"`
+	Total        int    `json:"total"`
+	CaptainID    int    `json:"captain_id"`
+
+	AutomaticSubs []Substitution `json:"automatic_subs"`
+}
+
+// MatchupError is the failure to score one matchup of a gameweek.
+type MatchupError struct {
+	MatchupID string
+	Err       error
+}
+
+func (e *MatchupError) Error() string {
+	return fmt.Sprintf("matchup %s: %v", e.MatchupID, e.Err)
+}
+
+func (e *MatchupError) Unwrap() error { return e.Err }
+
+// MatchupErrors returns the per-matchup failures in an error returned by ScoreGameweek.
+func MatchupErrors(err error) []*MatchupError {
+	var errs []*MatchupError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			var matchupErr *MatchupError
+			if errors.As(err, &matchupErr) {
+				errs = append(errs, matchupErr)
+			}
+		}
+	}
+	return errs
+}
+
+type Engine struct {
+	Logger       *log.Logger
+	FPL          *fpl.Client
+	MatchupStore stores.MatchupStore
+	PlayerStore  stores.PlayerStore
+}
+
+func NewEngine(logger *log.Logger, fplClient *fpl.Client, matchupStore stores.MatchupStore, playerStore stores.PlayerStore) *Engine {
+	return &Engine{
+		Logger:       logger,
+		FPL:          fplClient,
+		MatchupStore: matchupStore,
+		PlayerStore:  playerStore,
+	}
+}
+
+// ScoreGameweek computes both managers' scores for every matchup in the gameweek and
+// stores them, moving matchups whose betting has closed to live. Matchups that are not yet
+// open, still taking bets, settled or voided are left alone. A failure on one matchup does
+// not stop the others; their errors are returned joined as *MatchupError.
+func (e *Engine) ScoreGameweek(ctx context.Context, gameweek int) ([]*stores.Matchup, error) {
+	data, err := e.loadGameweek(ctx, gameweek)
+	if err != nil {
+		return nil, err
+	}
+
+	matchups, err := e.MatchupStore.GetGameweekMatchups(gameweek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matchups for gameweek %d: %w", gameweek, err)
+	}
+
+	scores := make(map[int]*ManagerScore)
+	var errs []error
+	for _, matchup := range matchups {
+		switch matchup.Status {
+		case stores.MatchupScheduled, stores.MatchupSettled, stores.MatchupVoided:
+			continue
+		case stores.MatchupOpen:
+			if matchup.BettingOpen(time.Now()) {
+				continue
+			}
+		}
+		home, err := e.scoreEntry(ctx, scores, matchup.HomeTeamID, gameweek, data)
+		if err != nil {
+			errs = append(errs, &MatchupError{MatchupID: matchup.ID, Err: err})
+			continue
+		}
+		away, err := e.scoreEntry(ctx, scores, matchup.AwayTeamID, gameweek, data)
+		if err != nil {
+			errs = append(errs, &MatchupError{MatchupID: matchup.ID, Err: err})
+			continue
+		}
+
+		if matchup.Status == stores.MatchupOpen || matchup.Status == stores.MatchupLocked {
+			if err := e.MatchupStore.AdvanceMatchup(matchup, stores.MatchupLive, "gameweek in progress"); err != nil {
+				errs = append(errs, &MatchupError{MatchupID: matchup.ID, Err: err})
+				continue
+			}
+		}
+		if err := e.MatchupStore.UpdateMatchup(home.Total, away.Total, matchup); err != nil {
+			errs = append(errs, &MatchupError{MatchupID: matchup.ID, Err: fmt.Errorf("failed to update scores: %w", err)})
+			continue
+		}
+		matchup.HomeTeamScore = home.Total
+		matchup.AwayTeamScore = away.Total
+		e.Logger.Printf("Scored matchup %s (GW%d): %d - %d", matchup.ID, gameweek, home.Total, away.Total)
+	}
+
+	return matchups, errors.Join(errs...)
+}
+
+// ScoreEntry computes a single manager's score for a gameweek.
+func (e *Engine) ScoreEntry(ctx context.Context, entryID, gameweek int) (*ManagerScore, error) {
+	data, err := e.loadGameweek(ctx, gameweek)
+	if err != nil {
+		return nil, err
+	}
+	return e.scoreEntry(ctx, make(map[int]*ManagerScore), entryID, gameweek, data)
+}
+
+func (e *Engine) loadGameweek(ctx context.Context, gameweek int) (*GameweekData, error) {
+	live, err := e.FPL.GetEventLive(ctx, gameweek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live points for gameweek %d: %w", gameweek, err)
+	}
+	fixtures, err := e.FPL.GetFixtures(ctx, gameweek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fixtures for gameweek %d: %w", gameweek, err)
+	}
+
+	data := &GameweekData{
+		Live:             make(map[int]fpl.LiveElement, len(live.Elements)),
+		FinishedFixtures: make(map[int]bool, len(fixtures)),
+		ElementTypes:     make(map[int]int),
+	}
+	for _, element := range live.Elements {
+		data.Live[element.ID] = element
+	}
+	for _, fixture := range fixtures {
+		data.FinishedFixtures[fixture.ID] = fixture.Finished || fixture.FinishedProvisional
+	}
+	return data, nil
+}
+
+func (e *Engine) scoreEntry(ctx context.Context, scores map[int]*ManagerScore, entryID, gameweek int, data *GameweekData) (*ManagerScore, error) {
+	if score, ok := scores[entryID]; ok {
+		return score, nil
+	}
+
+	picks, err := e.FPL.GetEntryPicks(ctx, entryID, gameweek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get picks for entry %d: %w", entryID, err)
+	}
+
+	ids := make([]int, 0, len(picks.Picks))
+	for _, pick := range picks.Picks {
+		if _, ok := data.ElementTypes[pick.Element]; !ok {
+			ids = append(ids, pick.Element)
+		}
+	}
+	if len(ids) > 0 {
+		elementTypes, err := e.PlayerStore.GetElementTypes(ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get element types for entry %d: %w", entryID, err)
+		}
+		for id, elementType := range elementTypes {
+			data.ElementTypes[id] = elementType
+		}
+	}
+
+	score := Calculate(picks, data)
+	score.EntryID = entryID
+	score.Gameweek = gameweek
+	scores[entryID] = score
+	return score, nil
+}
+
+// Calculate applies automatic substitutions, captaincy, chips and transfer hits to a
+// manager's picks. The vice-captain inherits the captain's multiplier when the captain
+// did not play. Bench Boost counts all fifteen players, so no substitutions are made.
+func Calculate(picks *fpl.EntryPicks, data *GameweekData) *ManagerScore {
+	score := &ManagerScore{TransferCost: picks.EntryHistory.EventTransfersCost}
+	if picks.ActiveChip != nil {
+		score.ActiveChip = *picks.ActiveChip
+	}
+
+	captainMultiplier := 2
+	if score.ActiveChip == ChipTripleCaptain {
+		captainMultiplier = 3
+	}
+
+	captain, vice := 0, 0
+	for _, pick := range picks.Picks {
+		if pick.IsCaptain {
+			captain = pick.Element
+		}
+		if pick.IsViceCaptain {
+			vice = pick.Element
+		}
+	}
+	score.CaptainID = captain
+	if captain != 0 && data.didNotPlay(captain) {
+		score.CaptainID = 0
+		if vice != 0 && !data.didNotPlay(vice) {
+			score.CaptainID = vice
+		}
+	}
+
+	lineup := picks.Picks
+	if score.ActiveChip != ChipBenchBoost {
+		lineup, score.AutomaticSubs = applyAutoSubs(picks.Picks, data)
+	}
+
+	for _, pick := range lineup {
+		multiplier := 1
+		if pick.Element == score.CaptainID {
+			multiplier = captainMultiplier
+		}
+		score.Points += data.Live[pick.Element].Stats.TotalPoints * multiplier
+	}
+
+	score.Total = score.Points - score.TransferCost
+	return score
+}
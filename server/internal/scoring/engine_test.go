@@ -0,0 +1,104 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+)
+
+func TestCalculate(t *testing.T) {
+	const (
+		G = Goalkeeper
+		D = Defender
+		M = Midfielder
+		F = Forward
+	)
+	types := [15]int{G, D, D, D, D, M, M, M, M, F, F, G, D, M, F}
+
+	tests := []struct {
+		name         string
+		chip         string
+		transferCost int
+		minutes      map[int]int
+		wantPoints   int
+		wantCaptain  int
+		wantSubs     int
+	}{
+		{
+			name:        "captain doubled",
+			wantPoints:  43,
+			wantCaptain: 10,
+		},
+		{
+			name:        "triple captain",
+			chip:        ChipTripleCaptain,
+			wantPoints:  53,
+			wantCaptain: 10,
+		},
+		{
+			name:        "vice-captain takes the armband when the captain does not play",
+			minutes:     map[int]int{10: 0},
+			wantPoints:  30,
+			wantCaptain: 9,
+			wantSubs:    1,
+		},
+		{
+			name:        "no captain when neither plays",
+			minutes:     map[int]int{9: 0, 10: 0},
+			wantPoints:  22,
+			wantCaptain: 0,
+			wantSubs:    2,
+		},
+		{
+			name:        "bench boost counts all fifteen without subs",
+			chip:        ChipBenchBoost,
+			minutes:     map[int]int{10: 0},
+			wantPoints:  36,
+			wantCaptain: 9,
+		},
+		{
+			name:         "transfer hits come off the total",
+			transferCost: 8,
+			wantPoints:   43,
+			wantCaptain:  10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picks, data := squad(types, tt.minutes)
+			// everyone scores 2, except the vice-captain's 5 and the captain's 10; players
+			// who did not play score nothing
+			for element, live := range data.Live {
+				points := 2
+				switch element {
+				case 9:
+					points = 5
+				case 10:
+					points = 10
+				}
+				if live.Stats.Minutes == 0 {
+					points = 0
+				}
+				live.Stats.TotalPoints = points
+				data.Live[element] = live
+			}
+			picks[9].IsCaptain = true
+			picks[8].IsViceCaptain = true
+
+			entry := &fpl.EntryPicks{Picks: picks, EntryHistory: fpl.EntryHistory{EventTransfersCost: tt.transferCost}}
+			if tt.chip != "" {
+				entry.ActiveChip = &tt.chip
+			}
+			score := Calculate(entry, data)
+			if score.Points != tt.wantPoints || score.CaptainID != tt.wantCaptain {
+				t.Errorf("points %d with captain %d, want %d with captain %d", score.Points, score.CaptainID, tt.wantPoints, tt.wantCaptain)
+			}
+			if score.Total != tt.wantPoints-tt.transferCost {
+				t.Errorf("total = %d, want %d", score.Total, tt.wantPoints-tt.transferCost)
+			}
+			if len(score.AutomaticSubs) != tt.wantSubs {
+				t.Errorf("%d automatic subs, want %d", len(score.AutomaticSubs), tt.wantSubs)
+			}
+		})
+	}
+}
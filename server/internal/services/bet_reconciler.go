@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/mirror"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// BetReconciler checks the outcome stored for each bet against the BetPlaced event its
+// transaction emitted, correcting rows recorded with the wrong outcome. It is the data
+// migration for bets stored before outcomes followed the contract; see
+// migrations/00014_bet_outcomes.sql.
+type BetReconciler struct {
+	Logger       *log.Logger
+	Mirror       *mirror.Client
+	BetStore     stores.BetStore
+	MatchupStore stores.MatchupStore
+}
+
+func NewBetReconciler(logger *log.Logger, mirrorClient *mirror.Client, betStore stores.BetStore, matchupStore stores.MatchupStore) *BetReconciler {
+	return &BetReconciler{
+		Logger:       logger,
+		Mirror:       mirrorClient,
+		BetStore:     betStore,
+		MatchupStore: matchupStore,
+	}
+}
+
+// ReconcileResult counts what one reconciliation pass did. Missing bets have no matching
+// BetPlaced event yet and are checked again on the next pass.
+type ReconcileResult struct {
+	Checked   int `json:"checked"`
+	Corrected int `json:"corrected"`
+	Missing   int `json:"missing"`
+}
+
+// ReconcileOutcomes checks up to limit unreconciled bets.
+func (br *BetReconciler) ReconcileOutcomes(ctx context.Context, limit int) (*ReconcileResult, error) {
+	bets, err := br.BetStore.ListUnreconciledBets(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	byMatchup := make(map[string][]*stores.Bet)
+	for _, bet := range bets {
+		byMatchup[bet.MatchupID] = append(byMatchup[bet.MatchupID], bet)
+	}
+
+	result := &ReconcileResult{}
+	var errs []error
+	for matchupID, matchupBets := range byMatchup {
+		events, err := br.betPlacedEvents(ctx, matchupID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("matchup %s: %w", matchupID, err))
+			continue
+		}
+
+		for _, bet := range matchupBets {
+			event, ok := events[strings.ToLower(bet.TxnHash)]
+			if !ok {
+				result.Missing++
+				continue
+			}
+			if event.Outcome != bet.PredictedWinner {
+				br.Logger.Printf("Correcting bet %s outcome from %s to %s", bet.ID, bet.PredictedWinner, event.Outcome)
+				result.Corrected++
+			}
+			if err := br.BetStore.ReconcileBetOutcome(bet, event.Outcome); err != nil {
+				errs = append(errs, fmt.Errorf("bet %s: %w", bet.ID, err))
+				continue
+			}
+			result.Checked++
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// betPlacedEvents returns a matchup contract's BetPlaced events keyed by lowercase transaction hash.
+func (br *BetReconciler) betPlacedEvents(ctx context.Context, matchupID string) (map[string]*contracts.BetPlacedEvent, error) {
+	matchup, err := br.MatchupStore.GetMatchupByID(matchupID)
+	if err != nil {
+		return nil, err
+	}
+	events := make(map[string]*contracts.BetPlacedEvent)
+	if matchup == nil || matchup.ContractAddress == "" {
+		return events, nil
+	}
+
+	logs, err := br.Mirror.GetContractLogs(ctx, matchup.ContractAddress, mirror.LogFilter{Topic0: contracts.BetPlacedTopic})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of %s: %w", matchup.ContractAddress, err)
+	}
+	for _, l := range logs {
+		event, err := contracts.DecodeBetPlaced(l.Topics, l.Data)
+		if err != nil {
+			br.Logger.Printf("Skipping undecodable log %d of %s: %v", l.Index, l.TransactionHash, err)
+			continue
+		}
+		events[strings.ToLower(l.TransactionHash)] = event
+	}
+	return events, nil
+}
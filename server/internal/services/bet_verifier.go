@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/mirror"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+const (
+	// BetVerifyTimeout bounds how long verification waits for a transaction to reach the
+	// mirror node, which usually lags consensus by a few seconds.
+	BetVerifyTimeout      = 15 * time.Second
+	betVerifyPollInterval = time.Second
+)
+
+var (
+	// ErrInvalidBet is returned when a bet does not match the transaction it claims.
+	ErrInvalidBet = errors.New("bet does not match its transaction")
+	// ErrBetTransactionNotFound is returned when the mirror node has no record of a bet's
+	// transaction within BetVerifyTimeout.
+	ErrBetTransactionNotFound = errors.New("bet transaction not found")
+)
+
+// BetVerifier checks a submitted bet against its transaction record before it is stored.
+type BetVerifier struct {
+	Mirror       *mirror.Client
+	MatchupStore stores.MatchupStore
+	Timeout      time.Duration
+}
+
+func NewBetVerifier(mirrorClient *mirror.Client, matchupStore stores.MatchupStore) *BetVerifier {
+	return &BetVerifier{
+		Mirror:       mirrorClient,
+		MatchupStore: matchupStore,
+		Timeout:      BetVerifyTimeout,
+	}
+}
+
+// Verify checks that the bet's transaction succeeded before betting closed, called bet(uint8)
+// on the matchup's contract with the claimed outcome, sent the claimed amount and came from
+// the claimed address. Betting is judged by the transaction's consensus time, so a bet placed
+// in time is still accepted when it is submitted after the matchup has locked, and the
+// consensus time is kept as the bet's PlacedAt. The bet may name its transaction by hash or
+// Hedera transaction ID. On success VerifiedAt is set and TxnHash becomes the transaction's
+// Ethereum hash, so a transaction backs one bet whichever ID it was submitted with.
+func (bv *BetVerifier) Verify(ctx context.Context, bet *stores.Bet) error {
+	if bet.BetAmount <= 0 {
+		return fmt.Errorf("%w: bet amount must be positive", ErrInvalidBet)
+	}
+	matchup, err := bv.MatchupStore.GetMatchupByID(bet.MatchupID)
+	if err != nil {
+		return err
+	}
+	if matchup == nil {
+		return fmt.Errorf("%w: matchup %s not found", ErrInvalidBet, bet.MatchupID)
+	}
+	if matchup.ContractAddress == "" {
+		return fmt.Errorf("%w: matchup %s has no contract", ErrInvalidBet, bet.MatchupID)
+	}
+
+	result, err := bv.contractResult(ctx, bet.TxnHash)
+	if err != nil {
+		return err
+	}
+	placedAt, err := mirror.ParseTimestamp(result.Timestamp)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBet, err)
+	}
+	if !matchup.BettingOpen(placedAt) {
+		return fmt.Errorf("%w: transaction reached consensus at %s", ErrBettingClosed, placedAt.Format(time.RFC3339))
+	}
+
+	if result.Result != mirror.ResultSuccess {
+		return fmt.Errorf("%w: transaction did not succeed: %s", ErrInvalidBet, result.Result)
+	}
+	if !strings.EqualFold(result.To, matchup.ContractAddress) && !strings.EqualFold(result.Address, matchup.ContractAddress) {
+		return fmt.Errorf("%w: transaction called %s, not the matchup contract %s", ErrInvalidBet, result.To, matchup.ContractAddress)
+	}
+	outcome, err := contracts.DecodeBetCall(result.FunctionParameters)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBet, err)
+	}
+	if outcome != bet.PredictedWinner {
+		return fmt.Errorf("%w: transaction bet on %s, not %s", ErrInvalidBet, outcome, bet.PredictedWinner)
+	}
+	if want := int64(math.Round(bet.BetAmount * contracts.TinybarsPerHbar)); result.Amount != want {
+		return fmt.Errorf("%w: transaction sent %d tinybars, expected %d", ErrInvalidBet, result.Amount, want)
+	}
+	if !strings.EqualFold(result.From, bet.UserAddress) {
+		return fmt.Errorf("%w: transaction was sent by %s, not %s", ErrInvalidBet, result.From, bet.UserAddress)
+	}
+
+	bet.TxnHash = result.Hash
+	bet.AmountTinybars = &result.Amount
+	bet.PlacedAt = &placedAt
+	verifiedAt := time.Now().UTC()
+	bet.VerifiedAt = &verifiedAt
+	return nil
+}
+
+// contractResult polls the mirror node until the transaction appears or Timeout passes.
+func (bv *BetVerifier) contractResult(ctx context.Context, txnHash string) (*mirror.ContractResult, error) {
+	if txnHash == "" {
+		return nil, fmt.Errorf("%w: missing transaction hash", ErrInvalidBet)
+	}
+	ctx, cancel := context.WithTimeout(ctx, bv.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(betVerifyPollInterval)
+	defer ticker.Stop()
+	for {
+		result, err := bv.Mirror.GetContractResult(ctx, mirror.TransactionID(txnHash))
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			// the deadline passed while a lookup was in flight
+			return nil, ErrBetTransactionNotFound
+		}
+		if !errors.Is(err, mirror.ErrNotFound) {
+			return nil, fmt.Errorf("failed to look up transaction %s: %w", txnHash, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrBetTransactionNotFound
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/mirror"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+const (
+	testContract = "0x00000000000000000000000000000000000c0ffe"
+	testBettor   = "0x00000000000000000000000000000000000b0b00"
+	testTxHash   = "0x" + "ab12000000000000000000000000000000000000000000000000000000000000"
+	testTxID     = "0.0.4321@1700000000.000000001"
+)
+
+// matchupLookup serves GetMatchupByID from a map. Other MatchupStore methods are left nil.
+type matchupLookup struct {
+	stores.MatchupStore
+	matchups map[string]*stores.Matchup
+}
+
+func (s matchupLookup) GetMatchupByID(id string) (*stores.Matchup, error) {
+	return s.matchups[id], nil
+}
+
+func betCall(outcome stores.Outcome) string {
+	return contracts.BetSelector + fmt.Sprintf("%064x", int(outcome))
+}
+
+// newTestVerifier serves the given contract results from a mirror stand-in and verifies bets
+// against one open matchup, "m1", whose contract is testContract.
+func newTestVerifier(t *testing.T, results ...*mirror.ContractResult) (*BetVerifier, *mirror.StandIn) {
+	t.Helper()
+	standIn := mirror.NewStandIn()
+	for _, result := range results {
+		standIn.AddContractResult(result)
+	}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	bettingEnd := time.Now().Add(time.Hour)
+	store := matchupLookup{matchups: map[string]*stores.Matchup{
+		"m1": {ID: "m1", Status: stores.MatchupOpen, ContractAddress: testContract, BettingEndsAt: &bettingEnd},
+	}}
+	verifier := NewBetVerifier(mirror.NewClient(server.URL, server.Client()), store)
+	verifier.Timeout = 50 * time.Millisecond
+	return verifier, standIn
+}
+
+// placedBet is the contract result of a 2 HBAR bet on a home win by testBettor.
+func placedBet() *mirror.ContractResult {
+	return &mirror.ContractResult{
+		Address:            testContract,
+		Amount:             2 * contracts.TinybarsPerHbar,
+		From:               testBettor,
+		FunctionParameters: betCall(stores.OutcomeHome),
+		Hash:               testTxHash,
+		Result:             mirror.ResultSuccess,
+		Timestamp:          fmt.Sprintf("%d.000000001", time.Now().Add(-time.Minute).Unix()),
+		To:                 testContract,
+	}
+}
+
+func testBet() *stores.Bet {
+	return &stores.Bet{
+		UserAddress:     testBettor,
+		MatchupID:       "m1",
+		PredictedWinner: stores.OutcomeHome,
+		BetAmount:       2,
+		TxnHash:         testTxHash,
+	}
+}
+
+func TestBetVerifierVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  func(*mirror.ContractResult)
+		bet     func(*stores.Bet)
+		matchup func(*stores.Matchup)
+		wantErr error
+	}{
+		{
+			name: "valid bet",
+		},
+		{
+			name: "placed in time and submitted after betting closed",
+			matchup: func(m *stores.Matchup) {
+				closed := time.Now().Add(-time.Second)
+				m.BettingEndsAt = &closed
+				m.Status = stores.MatchupLocked
+			},
+		},
+		{
+			name: "placed after betting closed",
+			result: func(r *mirror.ContractResult) {
+				r.Timestamp = fmt.Sprintf("%d.000000001", time.Now().Add(2*time.Hour).Unix())
+			},
+			wantErr: ErrBettingClosed,
+		},
+		{
+			name:    "matchup without a betting end",
+			matchup: func(m *stores.Matchup) { m.BettingEndsAt = nil },
+		},
+		{
+			name: "wrong contract",
+			result: func(r *mirror.ContractResult) {
+				r.To = "0x00000000000000000000000000000000000bad00"
+				r.Address = r.To
+			},
+			wantErr: ErrInvalidBet,
+		},
+		{
+			name:    "wrong sender",
+			result:  func(r *mirror.ContractResult) { r.From = "0x00000000000000000000000000000000000e0e00" },
+			wantErr: ErrInvalidBet,
+		},
+		{
+			name:    "reverted transaction",
+			result:  func(r *mirror.ContractResult) { r.Result = "CONTRACT_REVERT_EXECUTED" },
+			wantErr: ErrInvalidBet,
+		},
+		{
+			name:    "amount mismatch",
+			bet:     func(b *stores.Bet) { b.BetAmount = 3 },
+			wantErr: ErrInvalidBet,
+		},
+		{
+			name:    "outcome mismatch",
+			bet:     func(b *stores.Bet) { b.PredictedWinner = stores.OutcomeAway },
+			wantErr: ErrInvalidBet,
+		},
+		{
+			name:    "unknown transaction",
+			bet:     func(b *stores.Bet) { b.TxnHash = "0x" + strings.Repeat("ff", 32) },
+			wantErr: ErrBetTransactionNotFound,
+		},
+		{
+			name:    "unknown matchup",
+			bet:     func(b *stores.Bet) { b.MatchupID = "m2" },
+			wantErr: ErrInvalidBet,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := placedBet()
+			if tt.result != nil {
+				tt.result(result)
+			}
+			bet := testBet()
+			if tt.bet != nil {
+				tt.bet(bet)
+			}
+			verifier, _ := newTestVerifier(t, result)
+			if tt.matchup != nil {
+				tt.matchup(verifier.MatchupStore.(matchupLookup).matchups["m1"])
+			}
+
+			err := verifier.Verify(context.Background(), bet)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() = %v, want %v", err, tt.wantErr)
+				}
+				if bet.VerifiedAt != nil {
+					t.Errorf("rejected bet marked verified")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() = %v", err)
+			}
+			if bet.VerifiedAt == nil {
+				t.Errorf("verified bet has no VerifiedAt")
+			}
+			if want, _ := mirror.ParseTimestamp(result.Timestamp); bet.PlacedAt == nil || !bet.PlacedAt.Equal(want) {
+				t.Errorf("PlacedAt = %v, want the consensus time %s", bet.PlacedAt, want)
+			}
+		})
+	}
+}
+
+// A transaction submitted once by hash and once by Hedera transaction ID must be stored under
+// the same hash, so the second bet is caught as a duplicate.
+func TestBetVerifierCanonicalHash(t *testing.T) {
+	verifier, standIn := newTestVerifier(t, placedBet())
+	standIn.AddTransactionID(testTxID, testTxHash)
+
+	for _, txn := range []string{testTxID, mirror.TransactionID(testTxID), strings.ToUpper(testTxHash)} {
+		bet := testBet()
+		bet.TxnHash = txn
+		if err := verifier.Verify(context.Background(), bet); err != nil {
+			t.Fatalf("Verify(%s) = %v", txn, err)
+		}
+		if bet.TxnHash != testTxHash {
+			t.Errorf("bet submitted as %s stored under %s, want %s", txn, bet.TxnHash, testTxHash)
+		}
+	}
+}
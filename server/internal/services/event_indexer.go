@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/mirror"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// EventIndexer ingests BetPlaced, Settled, Claimed, Voided and Refunded events from every
+// deployed matchup contract, so bets placed from any client end up in the database.
+type EventIndexer struct {
+	Logger       *log.Logger
+	Mirror       *mirror.Client
+	MatchupStore stores.MatchupStore
+	EventStore   stores.ContractEventStore
+}
+
+func NewEventIndexer(logger *log.Logger, mirrorClient *mirror.Client, matchupStore stores.MatchupStore, eventStore stores.ContractEventStore) *EventIndexer {
+	return &EventIndexer{
+		Logger:       logger,
+		Mirror:       mirrorClient,
+		MatchupStore: matchupStore,
+		EventStore:   eventStore,
+	}
+}
+
+// IndexResult counts the events one indexing pass ingested.
+type IndexResult struct {
+	Contracts   int `json:"contracts"`
+	Bets        int `json:"bets"`
+	Settlements int `json:"settlements"`
+	Claims      int `json:"claims"`
+	Voids       int `json:"voids"`
+	Refunds     int `json:"refunds"`
+}
+
+// ContractWatchPeriod is how long a settled or voided contract is still read for claims and
+// refunds by bettors who have not yet collected.
+const ContractWatchPeriod = 90 * 24 * time.Hour
+
+// IndexContracts reads the logs of every contract that may still emit events since its cursor.
+// Contracts whose winners have all claimed or whose bettors have all been refunded are skipped.
+// A failure on one contract does not stop the others; all errors are returned joined.
+func (ei *EventIndexer) IndexContracts(ctx context.Context) (*IndexResult, error) {
+	matchups, err := ei.MatchupStore.ListWatchedContracts(ContractWatchPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IndexResult{}
+	var errs []error
+	for _, matchup := range matchups {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		events, err := ei.indexContract(ctx, matchup)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("contract %s: %w", matchup.ContractAddress, err))
+			continue
+		}
+		result.Contracts++
+		result.Bets += len(events.Bets)
+		result.Settlements += len(events.Settlements)
+		result.Claims += len(events.Claims)
+		result.Voids += len(events.Voids)
+		result.Refunds += len(events.Refunds)
+	}
+	return result, errors.Join(errs...)
+}
+
+func (ei *EventIndexer) indexContract(ctx context.Context, matchup *stores.Matchup) (*stores.ContractEvents, error) {
+	cursor, err := ei.EventStore.GetCursor(matchup.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	if cursor == nil {
+		cursor = &stores.ContractCursor{ContractAddress: matchup.ContractAddress}
+	}
+
+	logs, err := ei.Mirror.GetContractLogs(ctx, matchup.ContractAddress, mirror.LogFilter{FromTimestamp: cursor.Timestamp})
+	if err != nil {
+		return nil, err
+	}
+
+	events := &stores.ContractEvents{}
+	next := *cursor
+	for _, l := range logs {
+		if l.Timestamp == cursor.Timestamp && l.Index <= cursor.LogIndex {
+			// ingested in an earlier pass
+			continue
+		}
+		next.Timestamp, next.LogIndex = l.Timestamp, l.Index
+		if err := ei.decodeLog(matchup, l, events); err != nil {
+			// a log we cannot decode now never will be, so move past it
+			ei.Logger.Printf("Skipping log %d of %s: %v", l.Index, l.TransactionHash, err)
+		}
+	}
+	if next == *cursor {
+		return events, nil
+	}
+
+	if err := ei.EventStore.SaveEvents(&next, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// decodeLog appends the event a log carries to events. Logs of other events are ignored.
+func (ei *EventIndexer) decodeLog(matchup *stores.Matchup, l *mirror.ContractLog, events *stores.ContractEvents) error {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+	event := stores.ContractEvent{
+		MatchupID:       matchup.ID,
+		ContractAddress: matchup.ContractAddress,
+		TxnHash:         l.TransactionHash,
+		LogIndex:        l.Index,
+		Timestamp:       l.Timestamp,
+	}
+
+	switch strings.ToLower(l.Topics[0]) {
+	case contracts.BetPlacedTopic:
+		placed, err := contracts.DecodeBetPlaced(l.Topics, l.Data)
+		if err != nil {
+			return err
+		}
+		events.Bets = append(events.Bets, &stores.PlacedBet{
+			ContractEvent:  event,
+			Bettor:         placed.Bettor,
+			Outcome:        placed.Outcome,
+			AmountTinybars: contracts.WeiToTinybars(placed.Amount),
+		})
+	case contracts.SettledTopic:
+		winner, err := contracts.DecodeSettled(l.Topics, l.Data)
+		if err != nil {
+			return err
+		}
+		events.Settlements = append(events.Settlements, &stores.Settlement{ContractEvent: event, Winner: winner})
+	case contracts.ClaimedTopic:
+		claimed, err := contracts.DecodeClaimed(l.Topics, l.Data)
+		if err != nil {
+			return err
+		}
+		events.Claims = append(events.Claims, &stores.Claim{
+			ContractEvent:  event,
+			Claimer:        claimed.Claimer,
+			PayoutTinybars: contracts.WeiToTinybars(claimed.Payout),
+		})
+	case contracts.VoidedTopic:
+		events.Voids = append(events.Voids, &event)
+	case contracts.RefundedTopic:
+		refunded, err := contracts.DecodeRefunded(l.Topics, l.Data)
+		if err != nil {
+			return err
+		}
+		events.Refunds = append(events.Refunds, &stores.RefundedStake{
+			ContractEvent:  event,
+			Bettor:         refunded.Bettor,
+			AmountTinybars: contracts.WeiToTinybars(refunded.Amount),
+		})
+	}
+	return nil
+}
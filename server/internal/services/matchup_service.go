@@ -0,0 +1,523 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+	"github.com/divin3circle/fplduel/server/internal/pricing"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+	"github.com/divin3circle/fplduel/server/internal/utils"
+)
+
+// MatchupService holds matchup workflows shared by the HTTP handlers and the scheduler.
+type MatchupService struct {
+	Logger       *log.Logger
+	FPL          *fpl.Client
+	MatchupStore stores.MatchupStore
+	SlateStore   stores.SlateStore
+	Deployer     contracts.ContractDeployer
+	Pricing      pricing.Model
+	// BettingEndOffset moves the close of betting relative to the gameweek deadline; a
+	// negative offset closes betting before the deadline.
+	BettingEndOffset time.Duration
+}
+
+func NewMatchupService(logger *log.Logger, fplClient *fpl.Client, deployer contracts.ContractDeployer, matchupStore stores.MatchupStore, slateStore stores.SlateStore) *MatchupService {
+	return &MatchupService{
+		Logger:       logger,
+		FPL:          fplClient,
+		MatchupStore: matchupStore,
+		SlateStore:   slateStore,
+		Deployer:     deployer,
+		Pricing:      pricing.DefaultStrengthModel(),
+	}
+}
+
+const (
+	DefaultSlateSize = 10
+	MaxSlateSize     = 50
+)
+
+// Contract deployment outbox settings.
+const (
+	MaxDeployAttempts = 5
+	DeployBaseDelay   = 30 * time.Second
+	DeployMaxDelay    = 30 * time.Minute
+	// DeployLease is how long a claimed deployment is held before another worker may retry it.
+	DeployLease = 10 * time.Minute
+)
+
+// ErrBettingClosed is returned when a slate or contract would open after betting has closed.
+var ErrBettingClosed = errors.New("betting has closed")
+
+// ErrInvalidSlateOptions is returned for slate options that can never produce a slate.
+var ErrInvalidSlateOptions = errors.New("invalid slate options")
+
+// SlateOptions selects where a slate's teams come from and how they are paired.
+//
+// Size defaults to DefaultSlateSize, or to the number of EntryIDs for the entries source, and
+// Source to the most valuable teams. League requires LeagueID and entries requires EntryIDs.
+// An empty Strategy means random. Seeded pairing requires Seed; random and rivalry pairing
+// draw one when it is nil, and the seed used is stored with the slate so it can be reproduced.
+type SlateOptions struct {
+	Size     int    `json:"size"`
+	Source   string `json:"source"`
+	LeagueID int    `json:"league_id"`
+	EntryIDs []int  `json:"entry_ids"`
+	Strategy string `json:"strategy"`
+	Seed     *int64 `json:"seed"`
+}
+
+// Validate fills in the default size and checks the options against what their source can
+// supply, so requests that could never produce a slate are rejected before any FPL call.
+// Errors wrap ErrInvalidSlateOptions.
+func (o *SlateOptions) Validate() error {
+	if o.Size == 0 {
+		o.Size = DefaultSlateSize
+		if o.Source == utils.TeamSourceEntries {
+			o.Size = len(o.EntryIDs)
+		}
+	}
+	if o.Size < 2 || o.Size%2 != 0 || o.Size > MaxSlateSize {
+		return fmt.Errorf("%w: size must be an even number between 2 and %d", ErrInvalidSlateOptions, MaxSlateSize)
+	}
+
+	switch o.Source {
+	case "", utils.TeamSourceMostValuable:
+		if o.Size > utils.MaxMostValuableTeams {
+			return fmt.Errorf("%w: %s source supplies at most %d teams", ErrInvalidSlateOptions, utils.TeamSourceMostValuable, utils.MaxMostValuableTeams)
+		}
+	case utils.TeamSourceLeague:
+		if o.LeagueID <= 0 {
+			return fmt.Errorf("%w: league source requires a league_id", ErrInvalidSlateOptions)
+		}
+	case utils.TeamSourceTopOverall:
+	case utils.TeamSourceEntries:
+		if len(o.EntryIDs) != o.Size {
+			return fmt.Errorf("%w: entries source requires exactly %d entry_ids", ErrInvalidSlateOptions, o.Size)
+		}
+	default:
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidSlateOptions, o.Source)
+	}
+	return nil
+}
+
+// CreateSlate generates and stores the upcoming gameweek's slate. Its matchups are stored with
+// a pending deployment and get their contracts from DeployPendingContracts. If the gameweek
+// already has an active slate, that slate is returned together with stores.ErrSlateExists.
+func (ms *MatchupService) CreateSlate(ctx context.Context, opts SlateOptions) (*stores.Slate, error) {
+	gameweek, err := utils.GetCurrentGameweek(ctx, ms.FPL)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := ms.SlateStore.GetActiveSlate(gameweek)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, stores.ErrSlateExists
+	}
+
+	slate, err := ms.generateSlate(ctx, gameweek, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = ms.SlateStore.CreateSlate(slate)
+	if errors.Is(err, stores.ErrSlateExists) {
+		// another request created the slate while we were generating ours
+		ms.Logger.Printf("Slate for gameweek %d was created concurrently, discarding %d generated matchups", gameweek, len(slate.Matchups))
+		existing, err := ms.SlateStore.GetActiveSlate(gameweek)
+		if err != nil {
+			return nil, err
+		}
+		return existing, stores.ErrSlateExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ms.Logger.Printf("Created slate %s with %d matchups for gameweek %d", slate.ID, len(slate.Matchups), gameweek)
+	return slate, nil
+}
+
+// RegenerateSlate generates a fresh slate for the upcoming gameweek and retires the active
+// one, if any. The old slate is only retired once the new one is ready to be stored. Its
+// matchups are voided and their bettors refunded, so a slate with confirmed bets is kept
+// with stores.ErrSlateHasBets unless force is set.
+func (ms *MatchupService) RegenerateSlate(ctx context.Context, opts SlateOptions, force bool) (*stores.Slate, error) {
+	gameweek, err := utils.GetCurrentGameweek(ctx, ms.FPL)
+	if err != nil {
+		return nil, err
+	}
+
+	slate, err := ms.generateSlate(ctx, gameweek, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := ms.SlateStore.ReplaceSlate(slate, force); err != nil {
+		return nil, err
+	}
+
+	ms.Logger.Printf("Regenerated slate %s with %d matchups for gameweek %d", slate.ID, len(slate.Matchups), gameweek)
+	return slate, nil
+}
+
+func (ms *MatchupService) generateSlate(ctx context.Context, gameweek int, opts SlateOptions) (*stores.Slate, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	source, err := ms.teamSource(opts)
+	if err != nil {
+		return nil, err
+	}
+	strategy, seed, err := ms.pairingStrategy(gameweek, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	matchups, err := utils.GetMatchups(ctx, ms.FPL, gameweek, source, opts.Size, strategy)
+	if err != nil {
+		return nil, err
+	}
+	if err := ms.priceMatchups(ctx, matchups); err != nil {
+		return nil, err
+	}
+	bettingEnd, err := ms.bettingEnd(ctx, gameweek)
+	if err != nil {
+		return nil, err
+	}
+	if !bettingEnd.After(time.Now()) {
+		return nil, fmt.Errorf("%w: betting for gameweek %d closed at %s", ErrBettingClosed, gameweek, bettingEnd.Format(time.RFC3339))
+	}
+	for _, matchup := range matchups {
+		matchup.BettingEndsAt = &bettingEnd
+	}
+
+	slate := &stores.Slate{
+		Gameweek:   gameweek,
+		Size:       opts.Size,
+		TeamSource: source.Name(),
+		Strategy:   strategy.Name(),
+		Seed:       seed,
+		Matchups:   matchups,
+	}
+	if league, ok := source.(*utils.LeagueSource); ok {
+		slate.LeagueID = &league.LeagueID
+	}
+	return slate, nil
+}
+
+// bettingEnd is the gameweek deadline moved by BettingEndOffset.
+func (ms *MatchupService) bettingEnd(ctx context.Context, gameweek int) (time.Time, error) {
+	deadline, err := utils.GetGameweekDeadline(ctx, ms.FPL, gameweek)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return deadline.Add(ms.BettingEndOffset).UTC(), nil
+}
+
+// EntryFetchConcurrency bounds the FPL entry requests made at once while pricing a slate.
+const EntryFetchConcurrency = 4
+
+// priceMatchups seeds each matchup's virtual pools from both teams' form. Ranks and gameweek
+// points are read from each team's entry, fetched once per team. A matchup whose entries
+// cannot be fetched is seeded with the flat pools instead of failing the slate.
+func (ms *MatchupService) priceMatchups(ctx context.Context, matchups []*stores.Matchup) error {
+	ids := make([]int, 0, 2*len(matchups))
+	for _, matchup := range matchups {
+		ids = append(ids, matchup.HomeTeamID, matchup.AwayTeamID)
+	}
+	entries := ms.getEntries(ctx, ids)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, matchup := range matchups {
+		home, away := entries[matchup.HomeTeamID], entries[matchup.AwayTeamID]
+		if home == nil || away == nil {
+			ms.Logger.Printf("Pricing matchup %d vs %d with flat pools: entries unavailable", matchup.HomeTeamID, matchup.AwayTeamID)
+			seeds := pricing.FixedSeeds()
+			matchup.VirtualPoolHome = seeds.Home
+			matchup.VirtualPoolDraw = seeds.Draw
+			matchup.VirtualPoolAway = seeds.Away
+			matchup.PricingModel = seeds.ModelVersion
+			continue
+		}
+		matchup.HomeTeamOverallRank = home.SummaryOverallRank
+		matchup.AwayTeamOverallRank = away.SummaryOverallRank
+		matchup.HomeTeamEventPoints = home.SummaryEventPoints
+		matchup.AwayTeamEventPoints = away.SummaryEventPoints
+
+		seeds := ms.Pricing.Seed(pricing.Inputs{
+			Home: pricing.TeamForm{
+				Value:       matchup.HomeTeamValue,
+				OverallRank: matchup.HomeTeamOverallRank,
+				EventPoints: matchup.HomeTeamEventPoints,
+				Transfers:   matchup.HomeTeamTransfers,
+			},
+			Away: pricing.TeamForm{
+				Value:       matchup.AwayTeamValue,
+				OverallRank: matchup.AwayTeamOverallRank,
+				EventPoints: matchup.AwayTeamEventPoints,
+				Transfers:   matchup.AwayTeamTransfers,
+			},
+		})
+		matchup.VirtualPoolHome = seeds.Home
+		matchup.VirtualPoolDraw = seeds.Draw
+		matchup.VirtualPoolAway = seeds.Away
+		matchup.PricingModel = seeds.ModelVersion
+	}
+	return nil
+}
+
+// getEntries fetches each distinct entry once, at most EntryFetchConcurrency at a time.
+// Entries that fail to load are logged and left out of the result.
+func (ms *MatchupService) getEntries(ctx context.Context, ids []int) map[int]*fpl.Entry {
+	entries := make(map[int]*fpl.Entry, len(ids))
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, EntryFetchConcurrency)
+	)
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			entry, err := ms.FPL.GetEntry(ctx, id)
+			if err != nil {
+				ms.Logger.Printf("Failed to get entry %d: %v", id, err)
+				return
+			}
+			mu.Lock()
+			entries[id] = entry
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return entries
+}
+
+// DeployPendingContracts claims matchups waiting for a contract, deploys one for each and
+// records the result. Failed attempts are retried with exponential backoff until
+// MaxDeployAttempts, after which the matchup is marked failed. It returns how many
+// contracts were deployed and how many attempts failed.
+func (ms *MatchupService) DeployPendingContracts(ctx context.Context, limit int) (int, int, error) {
+	matchups, err := ms.MatchupStore.ClaimPendingDeployments(limit, DeployLease)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deployed, failed := 0, 0
+	for _, matchup := range matchups {
+		if ctx.Err() != nil {
+			// unprocessed claims become available again once their lease expires
+			return deployed, failed, ctx.Err()
+		}
+
+		bettingEnd, err := ms.matchupBettingEnd(ctx, matchup)
+		if errors.Is(err, ErrBettingClosed) {
+			// a contract nobody can bet on is not worth deploying
+			failed++
+			ms.Logger.Printf("Not deploying contract for matchup %s: %v", matchup.ID, err)
+			if err := ms.MatchupStore.MarkDeploymentFailed(matchup.ID, err.Error(), nil); err != nil {
+				return deployed, failed, err
+			}
+			continue
+		}
+		var deployment *contracts.Deployment
+		if err == nil {
+			deployment, err = ms.deploy(ctx, matchup, bettingEnd)
+		}
+		if err != nil {
+			failed++
+			var retryAt *time.Time
+			if matchup.DeploymentAttempts < MaxDeployAttempts {
+				next := time.Now().Add(deployBackoff(matchup.DeploymentAttempts))
+				retryAt = &next
+			}
+			ms.Logger.Printf("Error deploying contract for matchup %s (attempt %d): %v", matchup.ID, matchup.DeploymentAttempts, err)
+			if err := ms.MatchupStore.MarkDeploymentFailed(matchup.ID, err.Error(), retryAt); err != nil {
+				return deployed, failed, err
+			}
+			continue
+		}
+
+		if err := ms.MatchupStore.MarkDeployed(matchup.ID, deployment.ContractAddress, deployment.TransactionHash, bettingEnd); err != nil {
+			// the contract exists on chain; keep the address in the logs so it can be recovered
+			ms.Logger.Printf("Error recording contract %s for matchup %s: %v", deployment.ContractAddress, matchup.ID, err)
+			return deployed, failed, err
+		}
+		deployed++
+	}
+	return deployed, failed, nil
+}
+
+// deploy deploys the matchup's contract, recording the contract create's transaction ID
+// before it is submitted. If an earlier attempt recorded one, that transaction is looked up
+// first: a worker may have created the contract and died before recording it.
+func (ms *MatchupService) deploy(ctx context.Context, matchup *stores.Matchup, bettingEnd time.Time) (*contracts.Deployment, error) {
+	if matchup.DeploymentTxID != "" {
+		deployment, err := ms.Deployer.FindDeployment(ctx, matchup.DeploymentTxID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up earlier deployment %s: %w", matchup.DeploymentTxID, err)
+		}
+		if deployment != nil {
+			ms.Logger.Printf("Recovered contract %s for matchup %s from transaction %s", deployment.ContractAddress, matchup.ID, matchup.DeploymentTxID)
+			return deployment, nil
+		}
+	}
+
+	params := contracts.DeployParams{
+		BettingEnd: bettingEnd,
+		OnSubmit: func(transactionID string) error {
+			return ms.MatchupStore.RecordDeploymentSubmitted(matchup.ID, transactionID)
+		},
+	}
+	params.SetVirtualPools(virtualPools(matchup))
+	return ms.Deployer.Deploy(ctx, params)
+}
+
+// matchupBettingEnd returns the matchup's betting end, working it out from the deadline for
+// matchups created before betting ends were stored. It returns ErrBettingClosed once it has passed.
+func (ms *MatchupService) matchupBettingEnd(ctx context.Context, matchup *stores.Matchup) (time.Time, error) {
+	if matchup.BettingEndsAt == nil {
+		bettingEnd, err := ms.bettingEnd(ctx, matchup.Gameweek)
+		if err != nil {
+			return time.Time{}, err
+		}
+		matchup.BettingEndsAt = &bettingEnd
+	}
+	if !matchup.BettingOpen(time.Now()) {
+		return time.Time{}, fmt.Errorf("%w at %s", ErrBettingClosed, matchup.BettingEndsAt.Format(time.RFC3339))
+	}
+	return *matchup.BettingEndsAt, nil
+}
+
+// LockExpiredMatchups locks every open matchup whose betting end has passed. Matchups created
+// before betting ends were stored first get one from their gameweek deadline, so they lock too.
+// A gameweek whose deadline cannot be read is skipped until the next run.
+func (ms *MatchupService) LockExpiredMatchups(ctx context.Context) (int, error) {
+	gameweeks, err := ms.MatchupStore.ListGameweeksWithoutBettingEnd()
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for _, gameweek := range gameweeks {
+		bettingEnd, err := ms.bettingEnd(ctx, gameweek)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gameweek %d: %w", gameweek, err))
+			continue
+		}
+		backfilled, err := ms.MatchupStore.BackfillBettingEnd(gameweek, bettingEnd)
+		if err != nil {
+			return 0, err
+		}
+		ms.Logger.Printf("Backfilled betting end %s for %d matchups in gameweek %d", bettingEnd.Format(time.RFC3339), backfilled, gameweek)
+	}
+
+	locked, err := ms.MatchupStore.LockExpiredMatchups()
+	if err != nil {
+		return 0, err
+	}
+	return locked, errors.Join(errs...)
+}
+
+// deployBackoff doubles DeployBaseDelay for every attempt already made, up to DeployMaxDelay.
+func deployBackoff(attempts int) time.Duration {
+	delay := DeployBaseDelay
+	for i := 1; i < attempts && delay < DeployMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, DeployMaxDelay)
+}
+
+// teamSource builds the source named in opts, which must have been validated.
+func (ms *MatchupService) teamSource(opts SlateOptions) (utils.TeamSource, error) {
+	switch opts.Source {
+	case "", utils.TeamSourceMostValuable:
+		return &utils.MostValuableSource{FPL: ms.FPL}, nil
+	case utils.TeamSourceLeague:
+		return &utils.LeagueSource{FPL: ms.FPL, LeagueID: opts.LeagueID}, nil
+	case utils.TeamSourceTopOverall:
+		return &utils.LeagueSource{FPL: ms.FPL, LeagueID: utils.OverallLeagueID}, nil
+	case utils.TeamSourceEntries:
+		return &utils.EntriesSource{FPL: ms.FPL, EntryIDs: opts.EntryIDs}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown source %q", ErrInvalidSlateOptions, opts.Source)
+	}
+}
+
+// pairingStrategy builds the strategy named in opts and returns the seed it shuffles with,
+// drawing a random one when the strategy needs a seed and opts has none. Strategies that do
+// not shuffle return a nil seed.
+func (ms *MatchupService) pairingStrategy(gameweek int, opts SlateOptions) (utils.PairingStrategy, *int64, error) {
+	seed := opts.Seed
+	if seed == nil && opts.Strategy != utils.PairingSeeded {
+		drawn := rand.Int63()
+		seed = &drawn
+	}
+
+	switch opts.Strategy {
+	case "", utils.PairingRandom:
+		return &utils.RandomPairing{Seed: *seed}, seed, nil
+	case utils.PairingSeeded:
+		if seed == nil {
+			return nil, nil, fmt.Errorf("%w: seeded pairing requires a seed", ErrInvalidSlateOptions)
+		}
+		return &utils.SeededPairing{RandomPairing: utils.RandomPairing{Seed: *seed}}, seed, nil
+	case utils.PairingValueBalanced:
+		return &utils.ValueBalancedPairing{}, nil, nil
+	case utils.PairingRankBalanced:
+		return &utils.RankBalancedPairing{FPL: ms.FPL}, nil, nil
+	case utils.PairingRivalry:
+		winners, err := ms.previousWinners(gameweek - 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &utils.RivalryPairing{Seed: *seed, PreviousWinners: winners}, seed, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown strategy %q", ErrInvalidSlateOptions, opts.Strategy)
+	}
+}
+
+// previousWinners returns the entry IDs that won their matchup in the given gameweek.
+func (ms *MatchupService) previousWinners(gameweek int) (map[int]bool, error) {
+	winners := map[int]bool{}
+	if gameweek < 1 {
+		return winners, nil
+	}
+	matchups, err := ms.MatchupStore.GetGameweekMatchups(gameweek)
+	if err != nil {
+		return nil, err
+	}
+	for _, matchup := range matchups {
+		switch {
+		case matchup.HomeTeamScore > matchup.AwayTeamScore:
+			winners[matchup.HomeTeamID] = true
+		case matchup.AwayTeamScore > matchup.HomeTeamScore:
+			winners[matchup.AwayTeamID] = true
+		}
+	}
+	return winners, nil
+}
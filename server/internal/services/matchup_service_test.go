@@ -0,0 +1,495 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+	"github.com/divin3circle/fplduel/server/internal/pricing"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// deploymentStore keeps the deployment queue in memory, claiming and recording deployments
+// the way PostgresMatchupStore does. Methods the deploy flow does not use are left nil.
+type deploymentStore struct {
+	stores.MatchupStore
+
+	mu       sync.Mutex
+	matchups map[string]*stores.Matchup
+}
+
+func newDeploymentStore(matchups ...*stores.Matchup) *deploymentStore {
+	s := &deploymentStore{matchups: map[string]*stores.Matchup{}}
+	now := time.Now()
+	for _, m := range matchups {
+		if m.Status == "" {
+			m.Status = stores.MatchupScheduled
+		}
+		if m.DeploymentStatus == "" {
+			m.DeploymentStatus = stores.DeploymentPending
+		}
+		if m.DeploymentNextAttemptAt == nil {
+			m.DeploymentNextAttemptAt = &now
+		}
+		s.matchups[m.ID] = m
+	}
+	return s
+}
+
+func (s *deploymentStore) ClaimPendingDeployments(limit int, lease time.Duration) ([]*stores.Matchup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*stores.Matchup
+	now := time.Now()
+	for _, m := range s.matchups {
+		queued := m.DeploymentStatus == stores.DeploymentPending || m.DeploymentStatus == stores.DeploymentDeploying
+		if queued && m.Status == stores.MatchupScheduled && !m.DeploymentNextAttemptAt.After(now) {
+			due = append(due, m)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*stores.Matchup, 0, len(due))
+	leaseEnd := now.Add(lease)
+	for _, m := range due {
+		m.DeploymentStatus = stores.DeploymentDeploying
+		m.DeploymentAttempts++
+		m.DeploymentNextAttemptAt = &leaseEnd
+		claim := *m
+		claimed = append(claimed, &claim)
+	}
+	return claimed, nil
+}
+
+func (s *deploymentStore) RecordDeploymentSubmitted(id, transactionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matchups[id].DeploymentTxID = transactionID
+	return nil
+}
+
+func (s *deploymentStore) MarkDeployed(id, contractAddress, txHash string, bettingEndsAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.matchups[id]
+	m.DeploymentStatus = stores.DeploymentDeployed
+	m.ContractAddress = contractAddress
+	m.DeploymentTxHash = txHash
+	m.BettingEndsAt = &bettingEndsAt
+	m.DeploymentError = ""
+	m.DeploymentNextAttemptAt = nil
+	if m.Status == stores.MatchupScheduled {
+		m.Status = stores.MatchupOpen
+	}
+	return nil
+}
+
+func (s *deploymentStore) MarkDeploymentFailed(id, reason string, retryAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.matchups[id]
+	m.DeploymentStatus = stores.DeploymentPending
+	if retryAt == nil {
+		m.DeploymentStatus = stores.DeploymentFailed
+	}
+	m.DeploymentError = reason
+	m.DeploymentNextAttemptAt = retryAt
+	return nil
+}
+
+func (s *deploymentStore) get(id string) stores.Matchup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.matchups[id]
+}
+
+func pendingMatchup(id string, bettingEnd time.Time) *stores.Matchup {
+	return &stores.Matchup{
+		ID:              id,
+		VirtualPoolHome: 120 * contracts.TinybarsPerHbar,
+		VirtualPoolDraw: 40 * contracts.TinybarsPerHbar,
+		VirtualPoolAway: 90 * contracts.TinybarsPerHbar,
+		BettingEndsAt:   &bettingEnd,
+	}
+}
+
+func newDeployService(store stores.MatchupStore, deployer contracts.ContractDeployer) *MatchupService {
+	return NewMatchupService(log.New(io.Discard, "", 0), nil, deployer, store, nil)
+}
+
+func TestDeployPendingContracts(t *testing.T) {
+	bettingEnd := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	store := newDeploymentStore(pendingMatchup("a", bettingEnd), pendingMatchup("b", bettingEnd))
+	deployer := contracts.NewFakeDeployer()
+	service := newDeployService(store, deployer)
+
+	deployed, failed, err := service.DeployPendingContracts(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("DeployPendingContracts: %v", err)
+	}
+	if deployed != 2 || failed != 0 {
+		t.Fatalf("deployed, failed = %d, %d, want 2, 0", deployed, failed)
+	}
+
+	calls := deployer.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("deployer called %d times, want 2", len(calls))
+	}
+	for _, params := range calls {
+		if !params.BettingEnd.Equal(bettingEnd) {
+			t.Errorf("betting end = %s, want %s", params.BettingEnd, bettingEnd)
+		}
+		if want := contracts.HbarToWei(120); params.VirtualPoolA.Cmp(want) != 0 {
+			t.Errorf("home pool = %s, want %s", params.VirtualPoolA, want)
+		}
+		if want := contracts.HbarToWei(40); params.VirtualPoolDraw.Cmp(want) != 0 {
+			t.Errorf("draw pool = %s, want %s", params.VirtualPoolDraw, want)
+		}
+		if want := contracts.HbarToWei(90); params.VirtualPoolB.Cmp(want) != 0 {
+			t.Errorf("away pool = %s, want %s", params.VirtualPoolB, want)
+		}
+	}
+
+	for i, id := range []string{"a", "b"} {
+		m := store.get(id)
+		if m.DeploymentStatus != stores.DeploymentDeployed || m.Status != stores.MatchupOpen {
+			t.Errorf("matchup %s: deployment %s, status %s, want deployed and open", id, m.DeploymentStatus, m.Status)
+		}
+		if want := fmt.Sprintf("0x%040x", i+1); m.ContractAddress != want {
+			t.Errorf("matchup %s: contract %s, want %s", id, m.ContractAddress, want)
+		}
+	}
+
+	deployed, failed, err = service.DeployPendingContracts(context.Background(), 10)
+	if err != nil || deployed != 0 || failed != 0 {
+		t.Errorf("second run = %d, %d, %v, want nothing left to deploy", deployed, failed, err)
+	}
+}
+
+func TestDeployPendingContractsRetries(t *testing.T) {
+	bettingEnd := time.Now().Add(48 * time.Hour)
+	store := newDeploymentStore(pendingMatchup("a", bettingEnd))
+	deployer := contracts.NewFakeDeployer()
+	deployer.FailNext(1)
+	service := newDeployService(store, deployer)
+
+	before := time.Now()
+	deployed, failed, err := service.DeployPendingContracts(context.Background(), 10)
+	if err != nil || deployed != 0 || failed != 1 {
+		t.Fatalf("first run = %d, %d, %v, want one failure", deployed, failed, err)
+	}
+	m := store.get("a")
+	if m.DeploymentStatus != stores.DeploymentPending || m.DeploymentAttempts != 1 {
+		t.Fatalf("after failure: deployment %s after %d attempts, want pending after 1", m.DeploymentStatus, m.DeploymentAttempts)
+	}
+	if m.DeploymentNextAttemptAt == nil || m.DeploymentNextAttemptAt.Before(before.Add(DeployBaseDelay)) {
+		t.Fatalf("next attempt at %v, want at least %s from now", m.DeploymentNextAttemptAt, DeployBaseDelay)
+	}
+	if m.DeploymentError == "" {
+		t.Errorf("deployment error not recorded")
+	}
+
+	// not due yet, so nothing is claimed
+	if deployed, failed, _ := service.DeployPendingContracts(context.Background(), 10); deployed+failed != 0 {
+		t.Fatalf("retried before the backoff passed")
+	}
+
+	store.matchups["a"].DeploymentNextAttemptAt = &before
+	deployed, failed, err = service.DeployPendingContracts(context.Background(), 10)
+	if err != nil || deployed != 1 || failed != 0 {
+		t.Fatalf("retry = %d, %d, %v, want one deployment", deployed, failed, err)
+	}
+	if m := store.get("a"); m.DeploymentStatus != stores.DeploymentDeployed || m.DeploymentAttempts != 2 {
+		t.Errorf("after retry: deployment %s after %d attempts, want deployed after 2", m.DeploymentStatus, m.DeploymentAttempts)
+	}
+}
+
+func TestDeployPendingContractsGivesUp(t *testing.T) {
+	matchup := pendingMatchup("a", time.Now().Add(48*time.Hour))
+	matchup.DeploymentAttempts = MaxDeployAttempts - 1
+	store := newDeploymentStore(matchup)
+	deployer := contracts.NewFakeDeployer()
+	deployer.FailNext(1)
+
+	_, failed, err := newDeployService(store, deployer).DeployPendingContracts(context.Background(), 10)
+	if err != nil || failed != 1 {
+		t.Fatalf("run = %d failed, %v, want one failure", failed, err)
+	}
+	if m := store.get("a"); m.DeploymentStatus != stores.DeploymentFailed || m.DeploymentNextAttemptAt != nil {
+		t.Errorf("after last attempt: deployment %s, next attempt %v, want failed with no retry", m.DeploymentStatus, m.DeploymentNextAttemptAt)
+	}
+}
+
+func TestDeployPendingContractsBettingClosed(t *testing.T) {
+	store := newDeploymentStore(pendingMatchup("a", time.Now().Add(-time.Minute)))
+	deployer := contracts.NewFakeDeployer()
+
+	_, failed, err := newDeployService(store, deployer).DeployPendingContracts(context.Background(), 10)
+	if err != nil || failed != 1 {
+		t.Fatalf("run = %d failed, %v, want one failure", failed, err)
+	}
+	if len(deployer.Calls()) != 0 {
+		t.Errorf("deployed a contract whose betting has closed")
+	}
+	if m := store.get("a"); m.DeploymentStatus != stores.DeploymentFailed {
+		t.Errorf("deployment %s, want failed", m.DeploymentStatus)
+	}
+}
+
+func TestDeployPendingContractsRecoversLostDeployment(t *testing.T) {
+	store := newDeploymentStore(pendingMatchup("a", time.Now().Add(48*time.Hour)))
+	deployer := contracts.NewFakeDeployer()
+	deployer.LoseNext(1)
+	service := newDeployService(store, deployer)
+
+	if _, failed, err := service.DeployPendingContracts(context.Background(), 10); err != nil || failed != 1 {
+		t.Fatalf("first run = %d failed, %v, want one failure", failed, err)
+	}
+	m := store.get("a")
+	if m.DeploymentTxID == "" {
+		t.Fatalf("contract create transaction not recorded before submitting")
+	}
+
+	past := time.Now().Add(-time.Second)
+	store.matchups["a"].DeploymentNextAttemptAt = &past
+	deployed, _, err := service.DeployPendingContracts(context.Background(), 10)
+	if err != nil || deployed != 1 {
+		t.Fatalf("retry = %d deployed, %v, want one", deployed, err)
+	}
+	if calls := len(deployer.Calls()); calls != 1 {
+		t.Errorf("deployer called %d times, want the lost contract recovered instead of redeployed", calls)
+	}
+	if m := store.get("a"); m.ContractAddress != fmt.Sprintf("0x%040x", 1) || m.Status != stores.MatchupOpen {
+		t.Errorf("recovered matchup has contract %q and status %s", m.ContractAddress, m.Status)
+	}
+}
+
+func TestPriceMatchups(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		var id int
+		if _, err := fmt.Sscanf(r.URL.Path, "/entry/%d/", &id); err != nil || id == 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %d, "summary_overall_rank": %d, "summary_event_points": 60}`, id, id*1000)
+	}))
+	t.Cleanup(server.Close)
+
+	service := newDeployService(nil, nil)
+	service.FPL = fpl.NewClient(server.URL, server.Client())
+	matchups := []*stores.Matchup{
+		{HomeTeamID: 1, AwayTeamID: 2},
+		{HomeTeamID: 2, AwayTeamID: 1},
+		{HomeTeamID: 1, AwayTeamID: 3},
+	}
+
+	if err := service.priceMatchups(context.Background(), matchups); err != nil {
+		t.Fatalf("priceMatchups: %v", err)
+	}
+	for path, n := range requests {
+		if n != 1 {
+			t.Errorf("%s requested %d times, want once", path, n)
+		}
+	}
+	for i, m := range matchups[:2] {
+		if m.PricingModel != pricing.StrengthModelVersion {
+			t.Errorf("matchup %d priced with %q, want %q", i, m.PricingModel, pricing.StrengthModelVersion)
+		}
+	}
+	if m := matchups[0]; m.HomeTeamOverallRank != 1000 || m.AwayTeamOverallRank != 2000 {
+		t.Errorf("ranks = %d, %d, want 1000, 2000", m.HomeTeamOverallRank, m.AwayTeamOverallRank)
+	}
+	flat := pricing.FixedSeeds()
+	if m := matchups[2]; m.PricingModel != flat.ModelVersion || m.VirtualPoolHome != flat.Home || m.VirtualPoolDraw != flat.Draw {
+		t.Errorf("matchup with a missing entry priced with %q, want the flat pools", m.PricingModel)
+	}
+}
+
+// lockStore records betting end backfills and locks open matchups whose betting end has
+// passed, as PostgresMatchupStore does.
+type lockStore struct {
+	stores.MatchupStore
+	matchups []*stores.Matchup
+}
+
+func (s *lockStore) ListGameweeksWithoutBettingEnd() ([]int, error) {
+	var gameweeks []int
+	for _, m := range s.matchups {
+		if m.BettingEndsAt == nil {
+			gameweeks = append(gameweeks, m.Gameweek)
+		}
+	}
+	return gameweeks, nil
+}
+
+func (s *lockStore) BackfillBettingEnd(gameweek int, bettingEndsAt time.Time) (int, error) {
+	n := 0
+	for _, m := range s.matchups {
+		if m.Gameweek == gameweek && m.BettingEndsAt == nil {
+			m.BettingEndsAt = &bettingEndsAt
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *lockStore) LockExpiredMatchups() (int, error) {
+	n := 0
+	for _, m := range s.matchups {
+		if m.Status == stores.MatchupOpen && !m.BettingOpen(time.Now()) {
+			m.Status = stores.MatchupLocked
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestLockExpiredMatchupsBackfillsBettingEnd(t *testing.T) {
+	deadline := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bootstrap-static/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"events": [{"id": 7, "deadline_time": %q}]}`, deadline.Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+
+	future := time.Now().Add(time.Hour)
+	store := &lockStore{matchups: []*stores.Matchup{
+		{ID: "legacy", Gameweek: 7, Status: stores.MatchupOpen},
+		{ID: "open", Gameweek: 8, Status: stores.MatchupOpen, BettingEndsAt: &future},
+	}}
+	service := newDeployService(store, nil)
+	service.FPL = fpl.NewClient(server.URL, server.Client())
+
+	locked, err := service.LockExpiredMatchups(context.Background())
+	if err != nil || locked != 1 {
+		t.Fatalf("LockExpiredMatchups = %d, %v, want 1 locked", locked, err)
+	}
+	legacy := store.matchups[0]
+	if legacy.BettingEndsAt == nil || !legacy.BettingEndsAt.Equal(deadline) {
+		t.Errorf("legacy betting end = %v, want the gameweek deadline %s", legacy.BettingEndsAt, deadline)
+	}
+	if legacy.Status != stores.MatchupLocked || store.matchups[1].Status != stores.MatchupOpen {
+		t.Errorf("statuses = %s, %s, want locked, open", legacy.Status, store.matchups[1].Status)
+	}
+}
+
+// slateRecorder keeps the slates it is asked to store. The gameweek never has an active slate.
+type slateRecorder struct {
+	stores.SlateStore
+	created []*stores.Slate
+}
+
+func (s *slateRecorder) GetActiveSlate(gameweek int) (*stores.Slate, error) { return nil, nil }
+
+func (s *slateRecorder) CreateSlate(slate *stores.Slate) error {
+	s.created = append(s.created, slate)
+	return nil
+}
+
+// CreateSlate runs end to end against the fixtures FPL_MODE=replay serves, with no network.
+func TestCreateSlateReplay(t *testing.T) {
+	server := fpl.NewReplayServer(filepath.Join("..", "..", "testdata", "fpl"))
+	t.Cleanup(server.Close)
+
+	slates := &slateRecorder{}
+	service := NewMatchupService(log.New(io.Discard, "", 0), fpl.NewClient(server.URL, server.Client()), nil, nil, slates)
+	// the recorded gameweek 6 deadline has passed, so move the close of betting past now
+	deadline := time.Date(2025, 9, 27, 10, 0, 0, 0, time.UTC)
+	service.BettingEndOffset = time.Since(deadline).Truncate(time.Hour) + 24*time.Hour
+
+	slate, err := service.CreateSlate(context.Background(), SlateOptions{})
+	if err != nil {
+		t.Fatalf("CreateSlate: %v", err)
+	}
+	if len(slates.created) != 1 || slates.created[0] != slate {
+		t.Fatalf("stored %d slates, want the created one", len(slates.created))
+	}
+	if slate.Gameweek != 6 || slate.Size != DefaultSlateSize || len(slate.Matchups) != DefaultSlateSize/2 {
+		t.Fatalf("slate = gameweek %d, size %d, %d matchups, want gameweek 6, size %d, %d matchups",
+			slate.Gameweek, slate.Size, len(slate.Matchups), DefaultSlateSize, DefaultSlateSize/2)
+	}
+
+	wantEnd := deadline.Add(service.BettingEndOffset)
+	seen := map[int]bool{}
+	for _, m := range slate.Matchups {
+		seen[m.HomeTeamID], seen[m.AwayTeamID] = true, true
+		if m.PricingModel != pricing.StrengthModelVersion {
+			t.Errorf("matchup %d vs %d priced with %q, want %q", m.HomeTeamID, m.AwayTeamID, m.PricingModel, pricing.StrengthModelVersion)
+		}
+		if m.BettingEndsAt == nil || !m.BettingEndsAt.Equal(wantEnd) {
+			t.Errorf("matchup %d vs %d closes at %v, want %s", m.HomeTeamID, m.AwayTeamID, m.BettingEndsAt, wantEnd)
+		}
+	}
+	if len(seen) != DefaultSlateSize {
+		t.Errorf("slate draws %d distinct teams, want %d", len(seen), DefaultSlateSize)
+	}
+}
+
+func TestSlateOptionsValidate(t *testing.T) {
+	ids := func(n int) []int {
+		entries := make([]int, n)
+		for i := range entries {
+			entries[i] = i + 1
+		}
+		return entries
+	}
+	tests := []struct {
+		name     string
+		opts     SlateOptions
+		wantSize int
+		wantErr  bool
+	}{
+		{name: "defaults", opts: SlateOptions{}, wantSize: DefaultSlateSize},
+		{name: "entries size defaults to the entry count", opts: SlateOptions{Source: "entries", EntryIDs: ids(4)}, wantSize: 4},
+		{name: "entries with matching size", opts: SlateOptions{Source: "entries", Size: 6, EntryIDs: ids(6)}, wantSize: 6},
+		{name: "entries with an odd count", opts: SlateOptions{Source: "entries", EntryIDs: ids(3)}, wantErr: true},
+		{name: "entries beyond the max", opts: SlateOptions{Source: "entries", EntryIDs: ids(MaxSlateSize + 2)}, wantErr: true},
+		{name: "entries without ids", opts: SlateOptions{Source: "entries"}, wantErr: true},
+		{name: "entries count differs from size", opts: SlateOptions{Source: "entries", Size: 4, EntryIDs: ids(6)}, wantErr: true},
+		{name: "most valuable beyond its list", opts: SlateOptions{Size: 12}, wantErr: true},
+		{name: "league without an id", opts: SlateOptions{Source: "league"}, wantErr: true},
+		{name: "unknown source", opts: SlateOptions{Source: "friends"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSlateOptions) {
+					t.Errorf("Validate() = %v, want %v", err, ErrInvalidSlateOptions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			if tt.opts.Size != tt.wantSize {
+				t.Errorf("size = %d, want %d", tt.opts.Size, tt.wantSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// OddsService prices matchups with the same arithmetic as the FPLMatchupBet contract, from
+// its virtual pools and the confirmed bets in the database.
+type OddsService struct {
+	MatchupStore stores.MatchupStore
+	BetStore     stores.BetStore
+}
+
+func NewOddsService(matchupStore stores.MatchupStore, betStore stores.BetStore) *OddsService {
+	return &OddsService{
+		MatchupStore: matchupStore,
+		BetStore:     betStore,
+	}
+}
+
+// OutcomeOdds prices one outcome. Amounts are decimal HBAR strings; Odds is the 1e18-scaled
+// value getOdds returns, and DecimalOdds the same as a float.
+type OutcomeOdds struct {
+	Outcome            stores.Outcome `json:"outcome"`
+	Odds               string         `json:"odds"`
+	DecimalOdds        float64        `json:"decimal_odds"`
+	ImpliedProbability float64        `json:"implied_probability"`
+	Pool               string         `json:"pool"`
+	VirtualPool        string         `json:"virtual_pool"`
+
+	// Set when a stake is given: the odds once the stake is placed, and what the stake would
+	// claim if this outcome won and no further bets were placed.
+	OddsAfterStake          string `json:"odds_after_stake,omitempty"`
+	ProjectedPayout         string `json:"projected_payout,omitempty"`
+	ProjectedPayoutTinybars int64  `json:"projected_payout_tinybars,omitempty"`
+}
+
+type MatchupOdds struct {
+	MatchupID  string         `json:"matchup_id"`
+	TotalPool  string         `json:"total_pool"`
+	FeePercent int            `json:"fee_percent"`
+	Stake      string         `json:"stake,omitempty"`
+	Outcomes   []*OutcomeOdds `json:"outcomes"`
+}
+
+// GetOdds prices every outcome of a matchup. A positive stakeTinybars adds projections for
+// that stake. It returns nil if the matchup does not exist.
+func (s *OddsService) GetOdds(matchupID string, stakeTinybars int64) (*MatchupOdds, error) {
+	matchup, err := s.MatchupStore.GetMatchupByID(matchupID)
+	if err != nil || matchup == nil {
+		return nil, err
+	}
+	totals, err := s.BetStore.GetPoolTotals(matchupID)
+	if err != nil {
+		return nil, err
+	}
+
+	virtual := virtualPools(matchup)
+	real := realPools(totals)
+
+	odds := &MatchupOdds{
+		MatchupID:  matchup.ID,
+		TotalPool:  contracts.FormatEther(real.Total()),
+		FeePercent: contracts.FeePercent,
+	}
+	stake := contracts.TinybarsToWei(stakeTinybars)
+	if stakeTinybars > 0 {
+		odds.Stake = contracts.FormatEther(stake)
+	}
+
+	for _, outcome := range []stores.Outcome{stores.OutcomeHome, stores.OutcomeDraw, stores.OutcomeAway} {
+		value := contracts.Odds(virtual, real, outcome)
+		decimal, _ := new(big.Rat).SetFrac(value, contracts.WeiPerHbar).Float64()
+		outcomeOdds := &OutcomeOdds{
+			Outcome:            outcome,
+			Odds:               value.String(),
+			DecimalOdds:        decimal,
+			ImpliedProbability: contracts.ImpliedProbability(virtual, real, outcome),
+			Pool:               contracts.FormatEther(real.Pool(outcome)),
+			VirtualPool:        contracts.FormatEther(virtual.Pool(outcome)),
+		}
+		if stakeTinybars > 0 {
+			after := real.Add(outcome, stake)
+			payout := contracts.Payout(after, outcome, stake)
+			outcomeOdds.OddsAfterStake = contracts.Odds(virtual, after, outcome).String()
+			outcomeOdds.ProjectedPayout = contracts.FormatEther(payout)
+			outcomeOdds.ProjectedPayoutTinybars = contracts.WeiToTinybars(payout)
+		}
+		odds.Outcomes = append(odds.Outcomes, outcomeOdds)
+	}
+	return odds, nil
+}
+
+// PriceBet sets a bet's Odds to the decimal odds its outcome is quoted at before the bet is
+// recorded, so the stored odds are the contract's rather than whatever the client claimed.
+func (s *OddsService) PriceBet(bet *stores.Bet) error {
+	odds, err := s.GetOdds(bet.MatchupID, 0)
+	if err != nil {
+		return err
+	}
+	if odds == nil {
+		return fmt.Errorf("matchup %s not found", bet.MatchupID)
+	}
+	for _, outcome := range odds.Outcomes {
+		if outcome.Outcome == bet.PredictedWinner {
+			bet.Odds = outcome.DecimalOdds
+			return nil
+		}
+	}
+	return fmt.Errorf("no odds for outcome %s", bet.PredictedWinner)
+}
+
+// realPools returns the wei-scaled pools of per-outcome tinybar totals.
+func realPools(totals map[stores.Outcome]int64) contracts.Pools {
+	pools := contracts.NewPools()
+	for outcome, tinybars := range totals {
+		if outcome.Valid() {
+			pools = pools.Add(outcome, contracts.TinybarsToWei(tinybars))
+		}
+	}
+	return pools
+}
+
+// virtualPools returns the virtual pools a matchup's contract is seeded with.
+func virtualPools(matchup *stores.Matchup) contracts.Pools {
+	return contracts.Pools{
+		A:    contracts.TinybarsToWei(matchup.VirtualPoolHome),
+		Draw: contracts.TinybarsToWei(matchup.VirtualPoolDraw),
+		B:    contracts.TinybarsToWei(matchup.VirtualPoolAway),
+	}
+}
+
+// MarketSummary is a matchup's betting market: what has been staked and how, with the odds
+// its pools imply now.
+type MarketSummary struct {
+	*stores.BetMarket
+	Odds *MatchupOdds `json:"odds"`
+}
+
+// GetMarket summarises the betting on a matchup. It returns nil if the matchup does not exist.
+func (s *OddsService) GetMarket(matchupID string) (*MarketSummary, error) {
+	odds, err := s.GetOdds(matchupID, 0)
+	if err != nil || odds == nil {
+		return nil, err
+	}
+	market, err := s.BetStore.GetBetMarket(matchupID)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketSummary{BetMarket: market, Odds: odds}, nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// poolTotals serves GetPoolTotals from a map. Other BetStore methods are left nil.
+type poolTotals struct {
+	stores.BetStore
+	totals map[stores.Outcome]int64
+}
+
+func (s poolTotals) GetPoolTotals(matchupID string) (map[stores.Outcome]int64, error) {
+	return s.totals, nil
+}
+
+// A bet is priced from the pools, whatever odds the client was shown.
+func TestPriceBet(t *testing.T) {
+	matchup := &stores.Matchup{ID: "m1", VirtualPoolHome: 100, VirtualPoolDraw: 50, VirtualPoolAway: 50}
+	odds := NewOddsService(
+		matchupLookup{matchups: map[string]*stores.Matchup{"m1": matchup}},
+		poolTotals{totals: map[stores.Outcome]int64{stores.OutcomeHome: 100}},
+	)
+
+	tests := []struct {
+		outcome stores.Outcome
+		want    float64
+	}{
+		{outcome: stores.OutcomeHome, want: 1.5},
+		{outcome: stores.OutcomeDraw, want: 6},
+		{outcome: stores.OutcomeAway, want: 6},
+	}
+	for _, tt := range tests {
+		bet := &stores.Bet{MatchupID: "m1", PredictedWinner: tt.outcome, Odds: 99}
+		if err := odds.PriceBet(bet); err != nil {
+			t.Fatalf("PriceBet(%s) = %v", tt.outcome, err)
+		}
+		if bet.Odds != tt.want {
+			t.Errorf("%s odds = %v, want %v", tt.outcome, bet.Odds, tt.want)
+		}
+	}
+
+	if err := odds.PriceBet(&stores.Bet{MatchupID: "missing", PredictedWinner: stores.OutcomeHome}); err == nil {
+		t.Errorf("PriceBet(missing matchup) succeeded, want an error")
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// Bet results as reported with payouts.
+const (
+	BetPending     = "pending"
+	BetWon         = "won"
+	BetLost        = "lost"
+	BetVoided      = "voided"
+	BetUnconfirmed = "unconfirmed"
+)
+
+// PayoutService reports what a wallet's bets have won, with the same arithmetic as the
+// FPLMatchupBet contract's getClaimableAmount.
+type PayoutService struct {
+	BetStore stores.BetStore
+}
+
+func NewPayoutService(betStore stores.BetStore) *PayoutService {
+	return &PayoutService{BetStore: betStore}
+}
+
+// BetPayout is one bet's return. Payout is what the bet wins once its matchup settles:
+// stake × netPool / winningPool, or the whole stake back if the matchup was voided.
+// Claimable is the part of Payout the bettor can still claim. Amounts are decimal HBAR
+// strings with tinybar equivalents.
+type BetPayout struct {
+	Bet           *stores.Bet     `json:"bet"`
+	MatchupStatus string          `json:"matchup_status"`
+	Winner        *stores.Outcome `json:"winner"`
+	Result        string          `json:"result"`
+
+	Stake             string `json:"stake"`
+	StakeTinybars     int64  `json:"stake_tinybars"`
+	Payout            string `json:"payout"`
+	PayoutTinybars    int64  `json:"payout_tinybars"`
+	Claimable         string `json:"claimable"`
+	ClaimableTinybars int64  `json:"claimable_tinybars"`
+
+	Claimed     bool       `json:"claimed"`
+	ClaimTxHash string     `json:"claim_tx_hash,omitempty"`
+	ClaimedAt   *time.Time `json:"claimed_at,omitempty"`
+
+	RefundStatus string `json:"refund_status,omitempty"`
+	RefundTxHash string `json:"refund_tx_hash,omitempty"`
+}
+
+// WalletPayouts sums a wallet's bets. Claimed and refunded totals are what the contracts
+// actually transferred.
+type WalletPayouts struct {
+	Address                string       `json:"address"`
+	TotalStakedTinybars    int64        `json:"total_staked_tinybars"`
+	TotalPayoutTinybars    int64        `json:"total_payout_tinybars"`
+	TotalClaimableTinybars int64        `json:"total_claimable_tinybars"`
+	TotalClaimedTinybars   int64        `json:"total_claimed_tinybars"`
+	TotalRefundedTinybars  int64        `json:"total_refunded_tinybars"`
+	Bets                   []*BetPayout `json:"bets"`
+}
+
+// GetPayouts reports every bet placed from address, newest first. The contract pays each
+// bettor once per matchup, so a bettor with several bets on a matchup has their claim split
+// across them in proportion to stake.
+func (s *PayoutService) GetPayouts(address string) (*WalletPayouts, error) {
+	positions, err := s.BetStore.GetBetPositions(address)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet := &WalletPayouts{Address: address, Bets: make([]*BetPayout, 0, len(positions))}
+	claimed := make(map[string]bool)
+	refunded := make(map[string]bool)
+	for _, position := range positions {
+		payout := betPayout(position)
+		wallet.Bets = append(wallet.Bets, payout)
+
+		if position.Confirmed {
+			wallet.TotalStakedTinybars += payout.StakeTinybars
+		}
+		wallet.TotalPayoutTinybars += payout.PayoutTinybars
+		wallet.TotalClaimableTinybars += payout.ClaimableTinybars
+
+		matchupID := position.Bet.MatchupID
+		if position.ClaimTxHash != "" && !claimed[matchupID] {
+			claimed[matchupID] = true
+			wallet.TotalClaimedTinybars += position.ClaimPayoutTinybars
+		}
+		if position.RefundStatus == stores.RefundRefunded && !refunded[matchupID] {
+			refunded[matchupID] = true
+			wallet.TotalRefundedTinybars += position.RefundTinybars
+		}
+	}
+	return wallet, nil
+}
+
+func betPayout(position *stores.BetPosition) *BetPayout {
+	bet := position.Bet
+	stakeTinybars := int64(math.Round(bet.BetAmount * contracts.TinybarsPerHbar))
+	if bet.AmountTinybars != nil {
+		stakeTinybars = *bet.AmountTinybars
+	}
+	stake := contracts.TinybarsToWei(stakeTinybars)
+
+	payout := &BetPayout{
+		Bet:           bet,
+		MatchupStatus: position.MatchupStatus,
+		Winner:        position.Winner,
+		Result:        BetPending,
+		Stake:         contracts.FormatEther(stake),
+		StakeTinybars: stakeTinybars,
+		Claimed:       position.ClaimTxHash != "",
+		ClaimTxHash:   position.ClaimTxHash,
+		ClaimedAt:     position.ClaimedAt,
+		RefundStatus:  position.RefundStatus,
+		RefundTxHash:  position.RefundTxHash,
+	}
+
+	won := new(big.Int)
+	switch {
+	case !position.Confirmed:
+		payout.Result = BetUnconfirmed
+	case position.MatchupStatus == stores.MatchupVoided:
+		payout.Result = BetVoided
+		won.Set(stake)
+	case position.MatchupStatus == stores.MatchupSettled && position.Winner != nil:
+		payout.Result = BetLost
+		if bet.PredictedWinner == *position.Winner {
+			// the contract refuses claims on an empty winning pool, so this is zero then
+			won.Set(contracts.Payout(realPools(position.Pools), *position.Winner, stake))
+			if won.Sign() > 0 {
+				payout.Result = BetWon
+			}
+		}
+	}
+
+	payout.Payout = contracts.FormatEther(won)
+	payout.PayoutTinybars = contracts.WeiToTinybars(won)
+	claimable := new(big.Int)
+	if payout.Result == BetWon && !payout.Claimed {
+		claimable.Set(won)
+	}
+	payout.Claimable = contracts.FormatEther(claimable)
+	payout.ClaimableTinybars = contracts.WeiToTinybars(claimable)
+	return payout
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+func TestBetPayout(t *testing.T) {
+	const hbar = contracts.TinybarsPerHbar
+	home, draw := stores.OutcomeHome, stores.OutcomeDraw
+	// 10 HBAR on home and 10 on away; a winning 10 HBAR bet returns 19.6
+	pools := map[stores.Outcome]int64{stores.OutcomeHome: 10 * hbar, stores.OutcomeAway: 10 * hbar}
+
+	tests := []struct {
+		name          string
+		position      stores.BetPosition
+		wantResult    string
+		wantPayout    int64
+		wantClaimable int64
+	}{
+		{
+			name:       "unconfirmed",
+			position:   stores.BetPosition{MatchupStatus: stores.MatchupOpen},
+			wantResult: BetUnconfirmed,
+		},
+		{
+			name:       "open matchup",
+			position:   stores.BetPosition{MatchupStatus: stores.MatchupOpen, Confirmed: true},
+			wantResult: BetPending,
+		},
+		{
+			name:       "voided matchup returns the stake",
+			position:   stores.BetPosition{MatchupStatus: stores.MatchupVoided, Confirmed: true},
+			wantResult: BetVoided,
+			wantPayout: 10 * hbar,
+		},
+		{
+			name:          "won and unclaimed",
+			position:      stores.BetPosition{MatchupStatus: stores.MatchupSettled, Winner: &home, Pools: pools, Confirmed: true},
+			wantResult:    BetWon,
+			wantPayout:    1_960_000_000,
+			wantClaimable: 1_960_000_000,
+		},
+		{
+			name: "won and claimed",
+			position: stores.BetPosition{MatchupStatus: stores.MatchupSettled, Winner: &home, Pools: pools, Confirmed: true,
+				ClaimTxHash: "0xc1a1"},
+			wantResult: BetWon,
+			wantPayout: 1_960_000_000,
+		},
+		{
+			name:       "lost",
+			position:   stores.BetPosition{MatchupStatus: stores.MatchupSettled, Winner: &draw, Pools: pools, Confirmed: true},
+			wantResult: BetLost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := int64(10 * hbar)
+			position := tt.position
+			position.Bet = &stores.Bet{MatchupID: "m1", PredictedWinner: stores.OutcomeHome, BetAmount: 10, AmountTinybars: &amount}
+
+			payout := betPayout(&position)
+			if payout.Result != tt.wantResult {
+				t.Errorf("result = %s, want %s", payout.Result, tt.wantResult)
+			}
+			if payout.StakeTinybars != amount || payout.Stake != "10" {
+				t.Errorf("stake = %s (%d tinybars), want 10 HBAR", payout.Stake, payout.StakeTinybars)
+			}
+			if payout.PayoutTinybars != tt.wantPayout || payout.ClaimableTinybars != tt.wantClaimable {
+				t.Errorf("payout %d, claimable %d, want %d, %d", payout.PayoutTinybars, payout.ClaimableTinybars, tt.wantPayout, tt.wantClaimable)
+			}
+		})
+	}
+}
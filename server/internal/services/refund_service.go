@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// Refund outbox settings. Failed refunds and contract voids back off like deployments.
+const (
+	MaxRefundAttempts = 5
+	MaxVoidAttempts   = 5
+	// RefundLease is how long a claimed refund is held before another worker may retry it.
+	RefundLease = 10 * time.Minute
+)
+
+// ErrVoidReasonRequired is returned when a matchup is voided without saying why.
+var ErrVoidReasonRequired = errors.New("a reason is required to void a matchup")
+
+// RefundService cancels matchups that cannot be played, such as those of a postponed
+// gameweek, and returns every stake on their contracts.
+type RefundService struct {
+	Logger       *log.Logger
+	Settler      contracts.ContractSettler
+	MatchupStore stores.MatchupStore
+	RefundStore  stores.RefundStore
+}
+
+func NewRefundService(logger *log.Logger, settler contracts.ContractSettler, matchupStore stores.MatchupStore, refundStore stores.RefundStore) *RefundService {
+	return &RefundService{
+		Logger:       logger,
+		Settler:      settler,
+		MatchupStore: matchupStore,
+		RefundStore:  refundStore,
+	}
+}
+
+// RefundResult counts the work one refund pass did.
+type RefundResult struct {
+	Contracts int `json:"contracts"`
+	Refunded  int `json:"refunded"`
+	Failed    int `json:"failed"`
+}
+
+// VoidMatchup voids an unsettled matchup, then voids its contract and queues a refund for
+// each bettor. The matchup stays voided if the contract call fails; ProcessRefunds retries it.
+func (rs *RefundService) VoidMatchup(ctx context.Context, matchup *stores.Matchup, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrVoidReasonRequired
+	}
+	switch matchup.Status {
+	case stores.MatchupSettled:
+		return ErrAlreadySettled
+	case stores.MatchupVoided:
+		return ErrMatchupVoided
+	}
+
+	if err := rs.MatchupStore.VoidMatchup(matchup, reason); err != nil {
+		return err
+	}
+	rs.Logger.Printf("Voided matchup %s (GW%d): %s", matchup.ID, matchup.Gameweek, reason)
+	return rs.voidContract(ctx, matchup)
+}
+
+// ProcessRefunds voids the contracts of voided matchups that have not been voided on chain,
+// including those retired with their slate, then pays up to limit due refunds. A contract
+// whose void() fails is retried with backoff until MaxVoidAttempts.
+func (rs *RefundService) ProcessRefunds(ctx context.Context, limit int) (*RefundResult, error) {
+	matchups, err := rs.MatchupStore.ListUnvoidedContracts(MaxVoidAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RefundResult{}
+	var errs []error
+	for _, matchup := range matchups {
+		if err := rs.voidContract(ctx, matchup); err != nil {
+			errs = append(errs, fmt.Errorf("matchup %s: %w", matchup.ID, err))
+			continue
+		}
+		result.Contracts++
+	}
+
+	refunds, err := rs.RefundStore.ClaimDueRefunds(limit, RefundLease)
+	if err != nil {
+		return result, errors.Join(append(errs, err)...)
+	}
+	for _, refund := range refunds {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		tx, err := rs.Settler.Refund(ctx, refund.ContractAddress, refund.Bettor)
+		if err != nil {
+			var retryAt *time.Time
+			if refund.Attempts < MaxRefundAttempts {
+				next := time.Now().Add(deployBackoff(refund.Attempts))
+				retryAt = &next
+			}
+			if err := rs.RefundStore.MarkRefundFailed(refund.ID, err.Error(), retryAt); err != nil {
+				errs = append(errs, err)
+			}
+			rs.Logger.Printf("Error refunding %s on matchup %s (attempt %d): %v", refund.Bettor, refund.MatchupID, refund.Attempts, err)
+			result.Failed++
+			continue
+		}
+		if err := rs.RefundStore.MarkRefunded(refund.ID, tx.TransactionHash); err != nil {
+			// the stake is back with the bettor; keep the transaction in the logs so it can be recovered
+			rs.Logger.Printf("Error recording refund %s to %s: %v", tx.TransactionHash, refund.Bettor, err)
+			errs = append(errs, err)
+			continue
+		}
+		result.Refunded++
+	}
+	return result, errors.Join(errs...)
+}
+
+// voidContract queues refunds for a voided matchup's bettors and voids its contract, which
+// is what allows the refunds to be paid. Refunds are queued first so a failure between the
+// two steps is retried rather than leaving a voided contract with nobody to refund.
+func (rs *RefundService) voidContract(ctx context.Context, matchup *stores.Matchup) error {
+	if matchup.DeploymentStatus != stores.DeploymentDeployed || matchup.ContractAddress == "" {
+		// without a contract nobody could have bet on the matchup
+		return nil
+	}
+
+	queued, err := rs.RefundStore.CreateRefunds(matchup.ID)
+	if err != nil {
+		return fmt.Errorf("failed to queue refunds: %w", err)
+	}
+	if matchup.VoidTxHash != "" {
+		return nil
+	}
+	if !matchup.VoidSupported {
+		return fmt.Errorf("contract %s predates void()", matchup.ContractAddress)
+	}
+
+	tx, err := rs.Settler.Void(ctx, matchup.ContractAddress)
+	if err != nil {
+		attempts := matchup.VoidAttempts + 1
+		if attempts >= MaxVoidAttempts {
+			rs.Logger.Printf("Giving up voiding contract of matchup %s after %d attempts: %v", matchup.ID, attempts, err)
+		}
+		retryAt := time.Now().Add(deployBackoff(attempts))
+		if markErr := rs.MatchupStore.MarkContractVoidFailed(matchup.ID, err.Error(), retryAt); markErr != nil {
+			return errors.Join(err, markErr)
+		}
+		matchup.VoidAttempts = attempts
+		return err
+	}
+	if err := rs.MatchupStore.MarkContractVoided(matchup.ID, tx.TransactionHash); err != nil {
+		// the indexer records the Voided event, so the refunds still go out
+		rs.Logger.Printf("Error recording void %s of matchup %s: %v", tx.TransactionHash, matchup.ID, err)
+		return err
+	}
+	matchup.VoidTxHash = tx.TransactionHash
+	rs.Logger.Printf("Voided contract of matchup %s in %s, %d refunds queued", matchup.ID, tx.TransactionHash, queued)
+	return nil
+}
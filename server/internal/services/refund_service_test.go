@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+// voidStore keeps voided matchups in memory and lists unvoided contracts the way
+// PostgresMatchupStore does. Methods ProcessRefunds does not use are left nil.
+type voidStore struct {
+	stores.MatchupStore
+	matchups []*stores.Matchup
+}
+
+func (s *voidStore) ListUnvoidedContracts(maxAttempts int) ([]*stores.Matchup, error) {
+	var due []*stores.Matchup
+	now := time.Now()
+	for _, m := range s.matchups {
+		if m.VoidTxHash == "" && m.VoidSupported && m.VoidAttempts < maxAttempts &&
+			(m.VoidNextAttemptAt == nil || !m.VoidNextAttemptAt.After(now)) {
+			claim := *m
+			due = append(due, &claim)
+		}
+	}
+	return due, nil
+}
+
+func (s *voidStore) MarkContractVoided(id, txHash string) error {
+	s.get(id).VoidTxHash = txHash
+	return nil
+}
+
+func (s *voidStore) MarkContractVoidFailed(id, reason string, retryAt time.Time) error {
+	m := s.get(id)
+	m.VoidAttempts++
+	m.VoidError = reason
+	m.VoidNextAttemptAt = &retryAt
+	return nil
+}
+
+func (s *voidStore) get(id string) *stores.Matchup {
+	for _, m := range s.matchups {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// noRefunds is a RefundStore with no bettors to refund.
+type noRefunds struct {
+	stores.RefundStore
+}
+
+func (noRefunds) CreateRefunds(matchupID string) (int, error) { return 0, nil }
+
+func (noRefunds) ClaimDueRefunds(limit int, lease time.Duration) ([]*stores.Refund, error) {
+	return nil, nil
+}
+
+func voidedMatchup(id string) *stores.Matchup {
+	return &stores.Matchup{
+		ID:               id,
+		Status:           stores.MatchupVoided,
+		DeploymentStatus: stores.DeploymentDeployed,
+		ContractAddress:  "0x" + id,
+		VoidSupported:    true,
+	}
+}
+
+func TestProcessRefundsVoidsContracts(t *testing.T) {
+	legacy := voidedMatchup("legacy")
+	legacy.VoidSupported = false
+	store := &voidStore{matchups: []*stores.Matchup{voidedMatchup("ok"), voidedMatchup("failing"), legacy}}
+	settler := contracts.NewFakeSettler()
+	settler.Fail("0xfailing")
+	service := NewRefundService(log.New(io.Discard, "", 0), settler, store, noRefunds{})
+
+	before := time.Now()
+	result, err := service.ProcessRefunds(context.Background(), 10)
+	if err == nil {
+		t.Fatalf("ProcessRefunds did not report the failed void")
+	}
+	if result.Contracts != 1 || store.get("ok").VoidTxHash == "" {
+		t.Fatalf("voided %d contracts, want the supported one voided", result.Contracts)
+	}
+	if store.get("legacy").VoidAttempts != 0 {
+		t.Errorf("tried to void a contract without void()")
+	}
+	failing := store.get("failing")
+	if failing.VoidAttempts != 1 || failing.VoidNextAttemptAt == nil || failing.VoidNextAttemptAt.Before(before.Add(DeployBaseDelay)) {
+		t.Fatalf("after failure: %d attempts, next at %v, want 1 attempt backed off by %s", failing.VoidAttempts, failing.VoidNextAttemptAt, DeployBaseDelay)
+	}
+
+	// not due yet, so the failing contract is not retried
+	if _, err := service.ProcessRefunds(context.Background(), 10); err != nil || failing.VoidAttempts != 1 {
+		t.Fatalf("retried before the backoff passed: %d attempts, %v", failing.VoidAttempts, err)
+	}
+
+	for range MaxVoidAttempts {
+		failing.VoidNextAttemptAt = &before
+		service.ProcessRefunds(context.Background(), 10)
+	}
+	if failing.VoidAttempts != MaxVoidAttempts {
+		t.Errorf("void attempted %d times, want it to stop at %d", failing.VoidAttempts, MaxVoidAttempts)
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/internal/contracts"
+	"github.com/divin3circle/fplduel/server/internal/stores"
+)
+
+var (
+	ErrAlreadySettled = errors.New("matchup is already settled")
+	ErrNotDeployed    = errors.New("matchup contract is not deployed")
+	ErrMatchupVoided  = errors.New("matchup is voided")
+	ErrBettingOpen    = errors.New("betting on the matchup is still open")
+)
+
+// SettlementService settles matchup contracts once their scores are final, which is what
+// lets bettors claim.
+type SettlementService struct {
+	Logger       *log.Logger
+	Settler      contracts.ContractSettler
+	MatchupStore stores.MatchupStore
+}
+
+func NewSettlementService(logger *log.Logger, settler contracts.ContractSettler, matchupStore stores.MatchupStore) *SettlementService {
+	return &SettlementService{
+		Logger:       logger,
+		Settler:      settler,
+		MatchupStore: matchupStore,
+	}
+}
+
+// SettleMatchup marks a matchup's scores final and settles its contract. The winner follows
+// from the stored scores unless override is given.
+func (ss *SettlementService) SettleMatchup(ctx context.Context, matchup *stores.Matchup, override *stores.Outcome) error {
+	switch {
+	case matchup.Status == stores.MatchupSettled || matchup.SettledAt != nil:
+		return ErrAlreadySettled
+	case matchup.Status == stores.MatchupVoided:
+		return ErrMatchupVoided
+	case matchup.DeploymentStatus != stores.DeploymentDeployed || matchup.ContractAddress == "":
+		return ErrNotDeployed
+	case matchup.BettingOpen(time.Now()):
+		return ErrBettingOpen
+	}
+
+	if err := ss.MatchupStore.AdvanceMatchup(matchup, stores.MatchupFinal, "scores final"); err != nil {
+		return err
+	}
+
+	winner := stores.OutcomeForScores(matchup.HomeTeamScore, matchup.AwayTeamScore)
+	if override != nil {
+		winner = *override
+	}
+
+	settlement, err := ss.Settler.Settle(ctx, matchup.ContractAddress, winner)
+	if err != nil {
+		return err
+	}
+
+	if err := ss.MatchupStore.MarkSettled(matchup, settlement.Winner, settlement.TransactionHash, settlement.SettledAt); err != nil {
+		// the contract is settled on chain; keep the transaction in the logs so it can be recovered
+		ss.Logger.Printf("Error recording settlement %s of matchup %s: %v", settlement.TransactionHash, matchup.ID, err)
+		return err
+	}
+	ss.Logger.Printf("Settled matchup %s (GW%d) with outcome %d in %s", matchup.ID, matchup.Gameweek, winner, settlement.TransactionHash)
+	return nil
+}
+
+// SettleGameweek settles every deployed, unsettled matchup of a gameweek from its stored scores.
+// Matchups without a contract are skipped. Failures are collected so one bad contract does not
+// block the rest.
+func (ss *SettlementService) SettleGameweek(ctx context.Context, gameweek int) (int, error) {
+	return ss.SettleGameweekExcept(ctx, gameweek, nil)
+}
+
+// SettleGameweekExcept is SettleGameweek leaving out the matchups in skip, such as those whose
+// final scores could not be computed.
+func (ss *SettlementService) SettleGameweekExcept(ctx context.Context, gameweek int, skip map[string]bool) (int, error) {
+	matchups, err := ss.MatchupStore.GetGameweekMatchups(gameweek)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get matchups for gameweek %d: %w", gameweek, err)
+	}
+
+	settled := 0
+	var errs []error
+	for _, matchup := range matchups {
+		if matchup.Status == stores.MatchupSettled || matchup.Status == stores.MatchupVoided || skip[matchup.ID] {
+			continue
+		}
+		if matchup.DeploymentStatus != stores.DeploymentDeployed {
+			// without a contract nobody could have bet on the matchup
+			ss.Logger.Printf("Skipping settlement of matchup %s: deployment is %s", matchup.ID, matchup.DeploymentStatus)
+			continue
+		}
+		if err := ss.SettleMatchup(ctx, matchup, nil); err != nil {
+			errs = append(errs, fmt.Errorf("matchup %s: %w", matchup.ID, err))
+			continue
+		}
+		settled++
+	}
+	return settled, errors.Join(errs...)
+}
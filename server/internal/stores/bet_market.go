@@ -0,0 +1,150 @@
+package stores
+
+import (
+	"database/sql"
+	"time"
+)
+
+// OutcomeMarket aggregates the confirmed bets on one outcome, or on the whole matchup when
+// Outcome is nil. AverageOdds is the stake-weighted mean of the odds bettors were quoted;
+// bets indexed from the chain carry no odds and are left out of it, so it is nil when no
+// bet has known odds.
+type OutcomeMarket struct {
+	Outcome            *Outcome `json:"outcome,omitempty"`
+	Bets               int      `json:"bets"`
+	Bettors            int      `json:"bettors"`
+	StakedTinybars     int64    `json:"staked_tinybars"`
+	LargestBetTinybars int64    `json:"largest_bet_tinybars"`
+	AverageOdds        *float64 `json:"average_odds"`
+}
+
+// PoolSnapshot is the size of a matchup's pools at the end of an hour: the tinybars staked
+// on each outcome by confirmed bets placed up to then. Bets is the number placed in the hour.
+// Bets are placed at their transaction's consensus time, however they were recorded.
+type PoolSnapshot struct {
+	Hour          time.Time `json:"hour"`
+	Bets          int       `json:"bets"`
+	HomeTinybars  int64     `json:"home_tinybars"`
+	DrawTinybars  int64     `json:"draw_tinybars"`
+	AwayTinybars  int64     `json:"away_tinybars"`
+	TotalTinybars int64     `json:"total_tinybars"`
+}
+
+// BetMarket is the betting market on a matchup. Outcomes holds home, draw and away in that
+// order, with zero counts for an outcome nobody has backed.
+type BetMarket struct {
+	MatchupID string           `json:"matchup_id"`
+	Total     *OutcomeMarket   `json:"total"`
+	Outcomes  []*OutcomeMarket `json:"outcomes"`
+	History   []*PoolSnapshot  `json:"history"`
+}
+
+// GetBetMarket aggregates the confirmed bets on a matchup per outcome and overall, and the
+// hourly growth of its pools from the first bet's hour to the last's, gaps included.
+func (pbs *PostgresBetStore) GetBetMarket(matchupID string) (*BetMarket, error) {
+	query := `
+	SELECT CASE WHEN GROUPING(predicted_winner) = 0 THEN predicted_winner END,
+		COUNT(*),
+		COUNT(DISTINCT LOWER(user_address)),
+		COALESCE(SUM(stake), 0),
+		COALESCE(MAX(stake), 0),
+		SUM(odds * stake) FILTER (WHERE odds > 0) / NULLIF(SUM(stake) FILTER (WHERE odds > 0), 0)
+	FROM (
+		SELECT predicted_winner, user_address, odds, amount_tinybars AS stake
+		FROM bets
+		WHERE matchup_id = $1 AND (verified_at IS NOT NULL OR outcome_reconciled_at IS NOT NULL)
+	) confirmed
+	GROUP BY GROUPING SETS ((predicted_winner), ())
+	`
+	rows, err := pbs.db.Query(query, matchupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	market := &BetMarket{MatchupID: matchupID}
+	byOutcome := make(map[Outcome]*OutcomeMarket)
+	for rows.Next() {
+		m := &OutcomeMarket{}
+		var outcome sql.NullInt16
+		var averageOdds sql.NullFloat64
+		if err := rows.Scan(&outcome, &m.Bets, &m.Bettors, &m.StakedTinybars, &m.LargestBetTinybars, &averageOdds); err != nil {
+			return nil, err
+		}
+		if averageOdds.Valid {
+			m.AverageOdds = &averageOdds.Float64
+		}
+		if !outcome.Valid {
+			market.Total = m
+			continue
+		}
+		byOutcome[Outcome(outcome.Int16)] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if market.Total == nil {
+		market.Total = &OutcomeMarket{}
+	}
+	for _, outcome := range []Outcome{OutcomeHome, OutcomeDraw, OutcomeAway} {
+		m, ok := byOutcome[outcome]
+		if !ok {
+			m = &OutcomeMarket{}
+		}
+		m.Outcome = &outcome
+		market.Outcomes = append(market.Outcomes, m)
+	}
+
+	market.History, err = pbs.getPoolHistory(matchupID)
+	if err != nil {
+		return nil, err
+	}
+	return market, nil
+}
+
+func (pbs *PostgresBetStore) getPoolHistory(matchupID string) ([]*PoolSnapshot, error) {
+	query := `
+	WITH hourly AS (
+		SELECT date_trunc('hour', placed_at) AS hour,
+			COUNT(*) AS bets,
+			SUM(stake) FILTER (WHERE predicted_winner = $2) AS home,
+			SUM(stake) FILTER (WHERE predicted_winner = $3) AS draw,
+			SUM(stake) FILTER (WHERE predicted_winner = $4) AS away
+		FROM (
+			SELECT COALESCE(placed_at, created_at) AS placed_at, predicted_winner, amount_tinybars AS stake
+			FROM bets
+			WHERE matchup_id = $1 AND (verified_at IS NOT NULL OR outcome_reconciled_at IS NOT NULL)
+		) confirmed
+		GROUP BY 1
+	),
+	hours AS (
+		SELECT generate_series(MIN(hour), MAX(hour), INTERVAL '1 hour') AS hour
+		FROM hourly
+	)
+	SELECT h.hour,
+		COALESCE(b.bets, 0),
+		COALESCE(SUM(b.home) OVER w, 0)::BIGINT AS home,
+		COALESCE(SUM(b.draw) OVER w, 0)::BIGINT AS draw,
+		COALESCE(SUM(b.away) OVER w, 0)::BIGINT AS away
+	FROM hours h
+	LEFT JOIN hourly b ON b.hour = h.hour
+	WINDOW w AS (ORDER BY h.hour)
+	ORDER BY h.hour
+	`
+	rows, err := pbs.db.Query(query, matchupID, OutcomeHome, OutcomeDraw, OutcomeAway)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*PoolSnapshot{}
+	for rows.Next() {
+		s := &PoolSnapshot{}
+		if err := rows.Scan(&s.Hour, &s.Bets, &s.HomeTinybars, &s.DrawTinybars, &s.AwayTinybars); err != nil {
+			return nil, err
+		}
+		s.TotalTinybars = s.HomeTinybars + s.DrawTinybars + s.AwayTinybars
+		history = append(history, s)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,77 @@
+package stores
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetPoolHistory(t *testing.T) {
+	db := testDB(t)
+	store := NewPostgresBetStore(db)
+	hour := time.Date(2026, time.January, 10, 10, 0, 0, 0, time.UTC)
+	const hbar = TinybarsPerHbar
+
+	type bet struct {
+		outcome  Outcome
+		tinybars int64
+		placedAt time.Time
+	}
+	tests := []struct {
+		name string
+		bets []bet
+		want []PoolSnapshot
+	}{
+		{
+			name: "no bets",
+		},
+		{
+			name: "single hour",
+			bets: []bet{
+				{OutcomeHome, 10 * hbar, hour.Add(5 * time.Minute)},
+				{OutcomeDraw, 4 * hbar, hour.Add(40 * time.Minute)},
+			},
+			want: []PoolSnapshot{
+				{Hour: hour, Bets: 2, HomeTinybars: 10 * hbar, DrawTinybars: 4 * hbar, TotalTinybars: 14 * hbar},
+			},
+		},
+		{
+			name: "gaps filled with the running pools",
+			bets: []bet{
+				{OutcomeHome, 10 * hbar, hour.Add(5 * time.Minute)},
+				{OutcomeAway, 5 * hbar, hour.Add(3*time.Hour + 20*time.Minute)},
+			},
+			want: []PoolSnapshot{
+				{Hour: hour, Bets: 1, HomeTinybars: 10 * hbar, TotalTinybars: 10 * hbar},
+				{Hour: hour.Add(time.Hour), Bets: 0, HomeTinybars: 10 * hbar, TotalTinybars: 10 * hbar},
+				{Hour: hour.Add(2 * time.Hour), Bets: 0, HomeTinybars: 10 * hbar, TotalTinybars: 10 * hbar},
+				{Hour: hour.Add(3 * time.Hour), Bets: 1, HomeTinybars: 10 * hbar, AwayTinybars: 5 * hbar, TotalTinybars: 15 * hbar},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchupID := seedMatchup(t, db, 1, MatchupOpen, nil, nil)
+			for _, b := range tt.bets {
+				seedPlacedBet(t, db, matchupID, b.outcome, b.tinybars, b.placedAt)
+			}
+
+			history, err := store.getPoolHistory(matchupID)
+			if err != nil {
+				t.Fatalf("getPoolHistory: %v", err)
+			}
+			if len(history) != len(tt.want) {
+				t.Fatalf("got %d snapshots, want %d", len(history), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := *history[i]
+				if !got.Hour.Equal(want.Hour) {
+					t.Errorf("snapshot %d hour = %s, want %s", i, got.Hour, want.Hour)
+				}
+				got.Hour = want.Hour
+				if got != want {
+					t.Errorf("snapshot %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
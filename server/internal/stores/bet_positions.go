@@ -0,0 +1,95 @@
+package stores
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BetPosition is a bet together with what decides its return: its matchup's status and
+// winner, the confirmed pools on each outcome, and the bettor's claim or refund on the
+// matchup, if any. The contract pays per bettor, not per bet, so every bet a bettor placed
+// on a matchup shares the same claim and refund.
+type BetPosition struct {
+	Bet           *Bet
+	MatchupStatus string
+	Winner        *Outcome
+	SettledAt     *time.Time
+	// Pools is the tinybars staked on each outcome by confirmed bets.
+	Pools map[Outcome]int64
+	// Confirmed is whether the bet itself was confirmed on chain.
+	Confirmed bool
+
+	ClaimTxHash         string
+	ClaimPayoutTinybars int64
+	ClaimedAt           *time.Time
+
+	RefundStatus   string
+	RefundTxHash   string
+	RefundTinybars int64
+}
+
+// GetBetPositions returns every bet placed from an address, newest first, with its matchup's
+// result, pools and the address's claims and refunds.
+func (pbs *PostgresBetStore) GetBetPositions(userAddress string) ([]*BetPosition, error) {
+	query := `
+	WITH pools AS (
+		SELECT matchup_id,
+			SUM(CASE WHEN predicted_winner = $2 THEN stake ELSE 0 END) AS home,
+			SUM(CASE WHEN predicted_winner = $3 THEN stake ELSE 0 END) AS draw,
+			SUM(CASE WHEN predicted_winner = $4 THEN stake ELSE 0 END) AS away
+		FROM (
+			SELECT matchup_id, predicted_winner, amount_tinybars AS stake
+			FROM bets
+			WHERE (verified_at IS NOT NULL OR outcome_reconciled_at IS NOT NULL)
+				AND matchup_id IN (SELECT matchup_id FROM bets WHERE LOWER(user_address) = LOWER($1))
+		) confirmed
+		GROUP BY matchup_id
+	)
+	SELECT b.id, b.user_address, b.matchup_id, b.predicted_winner, b.bet_amount, b.odds, b.txn_hash,
+		b.created_at, b.updated_at, b.outcome_reconciled_at, b.verified_at, b.amount_tinybars, b.placed_at,
+		m.status, m.winner, m.settled_at,
+		COALESCE(p.home, 0), COALESCE(p.draw, 0), COALESCE(p.away, 0),
+		c.txn_hash, c.payout_tinybars, c.consensus_at,
+		r.status, r.txn_hash, r.amount_tinybars
+	FROM bets b
+	JOIN matchups m ON m.id = b.matchup_id
+	LEFT JOIN pools p ON p.matchup_id = b.matchup_id
+	LEFT JOIN LATERAL (
+		SELECT txn_hash, payout_tinybars, consensus_at FROM claims
+		WHERE matchup_id = b.matchup_id AND LOWER(claimer) = LOWER(b.user_address)
+		ORDER BY consensus_at
+		LIMIT 1
+	) c ON TRUE
+	LEFT JOIN refunds r ON r.matchup_id = b.matchup_id AND r.bettor = LOWER(b.user_address)
+	WHERE LOWER(b.user_address) = LOWER($1)
+	ORDER BY b.created_at DESC, b.id
+	`
+	rows, err := pbs.db.Query(query, userAddress, OutcomeHome, OutcomeDraw, OutcomeAway)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := []*BetPosition{}
+	for rows.Next() {
+		position := &BetPosition{Bet: &Bet{}}
+		var home, draw, away int64
+		var claimTxHash, refundStatus, refundTxHash sql.NullString
+		var claimPayout, refundAmount sql.NullInt64
+		fields := append(position.Bet.scanFields(),
+			&position.MatchupStatus, &position.Winner, &position.SettledAt,
+			&home, &draw, &away,
+			&claimTxHash, &claimPayout, &position.ClaimedAt,
+			&refundStatus, &refundTxHash, &refundAmount,
+		)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		position.Pools = map[Outcome]int64{OutcomeHome: home, OutcomeDraw: draw, OutcomeAway: away}
+		position.Confirmed = position.Bet.VerifiedAt != nil || position.Bet.OutcomeReconciledAt != nil
+		position.ClaimTxHash, position.ClaimPayoutTinybars = claimTxHash.String, claimPayout.Int64
+		position.RefundStatus, position.RefundTxHash, position.RefundTinybars = refundStatus.String, refundTxHash.String, refundAmount.Int64
+		positions = append(positions, position)
+	}
+	return positions, rows.Err()
+}
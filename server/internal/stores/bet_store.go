@@ -2,18 +2,27 @@ package stores
 
 import (
 	"database/sql"
+	"errors"
+	"time"
 )
 
+// ErrDuplicateBet is returned when a verified bet's transaction has already been recorded.
+var ErrDuplicateBet = errors.New("bet already recorded for this transaction")
+
 type Bet struct {
 	ID			string 	`json:"id"`
 	UserAddress	string	`json:"user_address"`
 	MatchupID	string 	`json:"matchup_id"`
-	PredictedWinner	int	`json:"predicted_winner"`
-	BetAmount	int		`json:"bet_amount"`
+	PredictedWinner	Outcome	`json:"predicted_winner"`
+	BetAmount	float64	`json:"bet_amount"`
 	Odds		float64	`json:"odds"`
 	TxnHash		string	`json:"txn_hash"`
 	CreatedAt	string	`json:"created_at"`
 	UpdatedAt	string	`json:"updated_at"`
+	OutcomeReconciledAt	*time.Time	`json:"outcome_reconciled_at,omitempty"`
+	VerifiedAt	*time.Time	`json:"verified_at,omitempty"`
+	AmountTinybars	*int64	`json:"amount_tinybars,omitempty"`
+	PlacedAt	*time.Time	`json:"placed_at,omitempty"`
 }
 
 type PostgresBetStore struct {
@@ -34,26 +43,92 @@ type BetCount struct {
 type BetStore interface {
 	CreateBet(bet *Bet) error
 	GetBetsByUserAddress(userAddress string) ([]*Bet, error)
+	GetBetByTxnHash(txnHash string) (*Bet, error)
 	GetNumberOfBets(matchup string) (*BetCount, error)
+	ListUnreconciledBets(limit int) ([]*Bet, error)
+	ReconcileBetOutcome(bet *Bet, outcome Outcome) error
+	GetPoolTotals(matchupID string) (map[Outcome]int64, error)
+	GetBetPositions(userAddress string) ([]*BetPosition, error)
+	GetBetMarket(matchupID string) (*BetMarket, error)
 }
 
+// CreateBet records a bet. A verified bet's outcome was read from its transaction, so it is
+// stored as already reconciled. The stake is AmountTinybars when set, as verification does
+// from the transaction, and BetAmount in tinybars otherwise. Recording a transaction twice
+// returns ErrDuplicateBet. PlacedAt is the transaction's consensus time, which verification
+// reads from the mirror node; without it the bet is taken as placed now.
 func (pbs *PostgresBetStore) CreateBet(bet *Bet) error {
 	query := `
-	INSERT INTO bets (user_address, matchup_id, predicted_winner, bet_amount, odds, txn_hash, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-	RETURNING id, created_at, updated_at
+	INSERT INTO bets (user_address, matchup_id, predicted_winner, bet_amount, amount_tinybars, odds, txn_hash, verified_at, outcome_reconciled_at, placed_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, COALESCE($5, ROUND($4::NUMERIC * $9)::BIGINT), $6, $7, $8, $8, COALESCE($10, NOW()), NOW(), NOW())
+	RETURNING id, amount_tinybars, placed_at, created_at, updated_at
 	`
-	return pbs.db.QueryRow(query, bet.UserAddress, bet.MatchupID, bet.PredictedWinner, bet.BetAmount, bet.Odds, bet.TxnHash).
-		Scan(&bet.ID, &bet.CreatedAt, &bet.UpdatedAt)
+	err := pbs.db.QueryRow(query, bet.UserAddress, bet.MatchupID, bet.PredictedWinner, bet.BetAmount, bet.AmountTinybars, bet.Odds, bet.TxnHash, bet.VerifiedAt, TinybarsPerHbar, bet.PlacedAt).
+		Scan(&bet.ID, &bet.AmountTinybars, &bet.PlacedAt, &bet.CreatedAt, &bet.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicateBet
+	}
+	if err != nil {
+		return err
+	}
+	bet.OutcomeReconciledAt = bet.VerifiedAt
+	return nil
 }
 
 func (pbs *PostgresBetStore) GetBetsByUserAddress(userAddress string) ([]*Bet, error) {
 	query := `
-	SELECT id, user_address, matchup_id, predicted_winner, bet_amount, odds, txn_hash, created_at, updated_at
+	SELECT ` + betColumns + `
+	FROM bets
+	WHERE LOWER(user_address) = LOWER($1)
+	`
+	bets, err := pbs.queryBets(query, userAddress)
+	if err != nil {
+		return nil, err
+	}
+	if bets == nil {
+		bets = []*Bet{}
+	}
+	return bets, nil
+}
+
+// GetBetByTxnHash returns the verified bet recorded for a transaction's hash, or nil if
+// there is none.
+func (pbs *PostgresBetStore) GetBetByTxnHash(txnHash string) (*Bet, error) {
+	query := `
+	SELECT ` + betColumns + `
 	FROM bets
-	WHERE user_address = $1
+	WHERE LOWER(txn_hash) = LOWER($1) AND verified_at IS NOT NULL
 	`
-	rows, err := pbs.db.Query(query, userAddress)
+	bets, err := pbs.queryBets(query, txnHash)
+	if err != nil || len(bets) == 0 {
+		return nil, err
+	}
+	return bets[0], nil
+}
+
+const betColumns = `id, user_address, matchup_id, predicted_winner, bet_amount, odds, txn_hash, created_at, updated_at, outcome_reconciled_at, verified_at, amount_tinybars, placed_at`
+
+// scanFields returns pointers to a bet's fields in betColumns order.
+func (bet *Bet) scanFields() []any {
+	return []any{
+		&bet.ID,
+		&bet.UserAddress,
+		&bet.MatchupID,
+		&bet.PredictedWinner,
+		&bet.BetAmount,
+		&bet.Odds,
+		&bet.TxnHash,
+		&bet.CreatedAt,
+		&bet.UpdatedAt,
+		&bet.OutcomeReconciledAt,
+		&bet.VerifiedAt,
+		&bet.AmountTinybars,
+		&bet.PlacedAt,
+	}
+}
+
+func (pbs *PostgresBetStore) queryBets(query string, args ...any) ([]*Bet, error) {
+	rows, err := pbs.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -62,17 +137,7 @@ func (pbs *PostgresBetStore) GetBetsByUserAddress(userAddress string) ([]*Bet, e
 	var bets []*Bet
 	for rows.Next() {
 		bet := &Bet{}
-		err := rows.Scan(
-			&bet.ID,
-			&bet.UserAddress,
-			&bet.MatchupID,
-			&bet.PredictedWinner,
-			&bet.BetAmount,
-			&bet.Odds,
-			&bet.TxnHash,
-			&bet.CreatedAt,
-			&bet.UpdatedAt,
-		)
+		err := rows.Scan(bet.scanFields()...)
 		if err != nil {
 			return nil, err
 		}
@@ -81,9 +146,6 @@ func (pbs *PostgresBetStore) GetBetsByUserAddress(userAddress string) ([]*Bet, e
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-	if bets == nil {
-		bets = []*Bet{}
-	}
 	return bets, nil
 }
 
@@ -99,17 +161,73 @@ func (pbs *PostgresBetStore) GetNumberOfBets(matchup string) (*BetCount, error)
 
 	query := `
 	SELECT 
-		COUNT(*) FILTER (WHERE predicted_winner = 0) AS team_a_bets,
-		COUNT(*) FILTER (WHERE predicted_winner = 1) AS team_b_bets,
-		COUNT(*) FILTER (WHERE predicted_winner = 2) AS draw_bets
+		COUNT(*) FILTER (WHERE predicted_winner = $2) AS team_a_bets,
+		COUNT(*) FILTER (WHERE predicted_winner = $3) AS draw_bets,
+		COUNT(*) FILTER (WHERE predicted_winner = $4) AS team_b_bets
 	FROM bets
 	WHERE matchup_id = $1
 	`
 	betCount := &BetCount{}
-	err = pbs.db.QueryRow(query, matchup).Scan(&betCount.TeamABets, &betCount.TeamBBets, &betCount.DrawBets)
+	err = pbs.db.QueryRow(query, matchup, OutcomeHome, OutcomeDraw, OutcomeAway).Scan(&betCount.TeamABets, &betCount.DrawBets, &betCount.TeamBBets)
 	if err != nil {
 		return nil, err
 	}
 	betCount.TotalBets = betCount.TeamABets + betCount.TeamBBets + betCount.DrawBets
 	return betCount, nil
 }
+
+// ListUnreconciledBets returns bets whose outcome has not yet been checked against the chain,
+// oldest first.
+func (pbs *PostgresBetStore) ListUnreconciledBets(limit int) ([]*Bet, error) {
+	query := `
+	SELECT ` + betColumns + `
+	FROM bets
+	WHERE outcome_reconciled_at IS NULL
+	ORDER BY created_at
+	LIMIT $1
+	`
+	return pbs.queryBets(query, limit)
+}
+
+// ReconcileBetOutcome stores the outcome a bet was actually placed on and marks it checked.
+func (pbs *PostgresBetStore) ReconcileBetOutcome(bet *Bet, outcome Outcome) error {
+	query := `
+	UPDATE bets
+	SET predicted_winner = $1, outcome_reconciled_at = NOW(), updated_at = NOW()
+	WHERE id = $2
+	RETURNING updated_at, outcome_reconciled_at
+	`
+	err := pbs.db.QueryRow(query, outcome, bet.ID).Scan(&bet.UpdatedAt, &bet.OutcomeReconciledAt)
+	if err != nil {
+		return err
+	}
+	bet.PredictedWinner = outcome
+	return nil
+}
+
+// GetPoolTotals returns the tinybars staked on each outcome of a matchup. Only bets confirmed
+// on chain, by verification or a BetPlaced event, are counted.
+func (pbs *PostgresBetStore) GetPoolTotals(matchupID string) (map[Outcome]int64, error) {
+	query := `
+	SELECT predicted_winner, COALESCE(SUM(amount_tinybars), 0)
+	FROM bets
+	WHERE matchup_id = $1 AND (verified_at IS NOT NULL OR outcome_reconciled_at IS NOT NULL)
+	GROUP BY predicted_winner
+	`
+	rows, err := pbs.db.Query(query, matchupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[Outcome]int64)
+	for rows.Next() {
+		var outcome Outcome
+		var total int64
+		if err := rows.Scan(&outcome, &total); err != nil {
+			return nil, err
+		}
+		totals[outcome] = total
+	}
+	return totals, rows.Err()
+}
@@ -0,0 +1,272 @@
+package stores
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ContractCursor is the last log the event indexer has ingested for a contract. Timestamp is
+// the mirror node's consensus timestamp ("seconds.nanoseconds").
+type ContractCursor struct {
+	ContractAddress string    `json:"contract_address"`
+	Timestamp       string    `json:"timestamp"`
+	LogIndex        int       `json:"log_index"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ContractEvent identifies the log an indexed event was decoded from.
+type ContractEvent struct {
+	MatchupID       string `json:"matchup_id"`
+	ContractAddress string `json:"contract_address"`
+	TxnHash         string `json:"txn_hash"`
+	LogIndex        int    `json:"log_index"`
+	Timestamp       string `json:"timestamp"`
+}
+
+// PlacedBet is a BetPlaced event.
+type PlacedBet struct {
+	ContractEvent
+	Bettor         string  `json:"bettor"`
+	Outcome        Outcome `json:"outcome"`
+	AmountTinybars int64   `json:"amount_tinybars"`
+}
+
+// Settlement is a Settled event.
+type Settlement struct {
+	ID string `json:"id"`
+	ContractEvent
+	Winner    Outcome   `json:"winner"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Claim is a Claimed event. PayoutTinybars is what the contract transferred to the claimer.
+type Claim struct {
+	ID string `json:"id"`
+	ContractEvent
+	Claimer        string    `json:"claimer"`
+	PayoutTinybars int64     `json:"payout_tinybars"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RefundedStake is a Refunded event. AmountTinybars is what the contract returned to the bettor.
+type RefundedStake struct {
+	ContractEvent
+	Bettor         string `json:"bettor"`
+	AmountTinybars int64  `json:"amount_tinybars"`
+}
+
+// ContractEvents is a batch of decoded events from one contract. Voids are Voided events,
+// which carry nothing beyond the log they came from.
+type ContractEvents struct {
+	Bets        []*PlacedBet
+	Settlements []*Settlement
+	Claims      []*Claim
+	Voids       []*ContractEvent
+	Refunds     []*RefundedStake
+}
+
+type PostgresContractEventStore struct {
+	db *sql.DB
+}
+
+func NewPostgresContractEventStore(db *sql.DB) *PostgresContractEventStore {
+	return &PostgresContractEventStore{db: db}
+}
+
+type ContractEventStore interface {
+	GetCursor(contractAddress string) (*ContractCursor, error)
+	SaveEvents(cursor *ContractCursor, events *ContractEvents) error
+}
+
+// GetCursor returns a contract's cursor, or nil if none of its logs have been ingested.
+func (ps *PostgresContractEventStore) GetCursor(contractAddress string) (*ContractCursor, error) {
+	query := `
+	SELECT contract_address, last_timestamp, last_log_index, updated_at
+	FROM contract_cursors
+	WHERE contract_address = $1
+	`
+	cursor := &ContractCursor{}
+	err := ps.db.QueryRow(query, contractAddress).Scan(&cursor.ContractAddress, &cursor.Timestamp, &cursor.LogIndex, &cursor.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+// SaveEvents upserts a batch of events and advances the contract's cursor in one transaction,
+// so a batch is either fully ingested or read again on the next run. Every write is
+// idempotent.
+func (ps *PostgresContractEventStore) SaveEvents(cursor *ContractCursor, events *ContractEvents) error {
+	tx, err := ps.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, bet := range events.Bets {
+		if err := upsertPlacedBet(tx, bet); err != nil {
+			return err
+		}
+	}
+	for _, settlement := range events.Settlements {
+		if err := insertSettlement(tx, settlement); err != nil {
+			return err
+		}
+	}
+	for _, claim := range events.Claims {
+		if err := insertClaim(tx, claim); err != nil {
+			return err
+		}
+	}
+	for _, void := range events.Voids {
+		if err := markContractVoided(tx, void); err != nil {
+			return err
+		}
+	}
+	for _, refund := range events.Refunds {
+		if err := upsertRefund(tx, refund); err != nil {
+			return err
+		}
+	}
+
+	query := `
+	INSERT INTO contract_cursors (contract_address, last_timestamp, last_log_index, updated_at)
+	VALUES ($1, $2, $3, NOW())
+	ON CONFLICT (contract_address) DO UPDATE
+	SET last_timestamp = EXCLUDED.last_timestamp, last_log_index = EXCLUDED.last_log_index, updated_at = NOW()
+	RETURNING updated_at
+	`
+	err = tx.QueryRow(query, cursor.ContractAddress, cursor.Timestamp, cursor.LogIndex).Scan(&cursor.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsertPlacedBet completes the bet recorded for the transaction, or records it if the bet
+// was placed without going through the API. Bets are matched on the transaction's Ethereum
+// hash; a bet stored under its Hedera transaction ID before verification canonicalised
+// hashes is matched on its matchup, bettor, outcome and stake instead, and moved to the hash.
+func upsertPlacedBet(q querier, bet *PlacedBet) error {
+	query := `
+	UPDATE bets
+	SET predicted_winner = $1, amount_tinybars = $2, placed_at = to_timestamp($4::DOUBLE PRECISION),
+		outcome_reconciled_at = COALESCE(outcome_reconciled_at, NOW()), updated_at = NOW()
+	WHERE LOWER(txn_hash) = LOWER($3)
+	`
+	result, err := q.Exec(query, bet.Outcome, bet.AmountTinybars, bet.TxnHash, bet.Timestamp)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	query = `
+	UPDATE bets
+	SET txn_hash = $1, placed_at = to_timestamp($6::DOUBLE PRECISION),
+		outcome_reconciled_at = COALESCE(outcome_reconciled_at, NOW()), updated_at = NOW()
+	WHERE id = (
+		SELECT id FROM bets
+		WHERE matchup_id = $2 AND LOWER(user_address) = LOWER($3) AND predicted_winner = $4
+			AND amount_tinybars = $5 AND txn_hash NOT LIKE '0x%' AND verified_at IS NOT NULL
+		ORDER BY created_at, id
+		LIMIT 1
+		FOR UPDATE
+	)
+	`
+	result, err = q.Exec(query, bet.TxnHash, bet.MatchupID, bet.Bettor, bet.Outcome, bet.AmountTinybars, bet.Timestamp)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	// odds at the time of the bet are unknown for bets placed elsewhere
+	query = `
+	INSERT INTO bets (user_address, matchup_id, predicted_winner, bet_amount, amount_tinybars, odds, txn_hash,
+		verified_at, outcome_reconciled_at, placed_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4::NUMERIC / $7, $4, 0, $5, NOW(), NOW(), to_timestamp($6::DOUBLE PRECISION), to_timestamp($6::DOUBLE PRECISION), NOW())
+	`
+	_, err = q.Exec(query, bet.Bettor, bet.MatchupID, bet.Outcome, bet.AmountTinybars, bet.TxnHash, bet.Timestamp, TinybarsPerHbar)
+	return err
+}
+
+// insertSettlement records a Settled event and, if the matchup was settled outside this
+// server, its winner. The chain is authoritative here, so the matchup becomes settled from
+// whatever status it was in.
+func insertSettlement(q querier, settlement *Settlement) error {
+	query := `
+	INSERT INTO settlements (matchup_id, contract_address, winner, txn_hash, log_index, consensus_at)
+	VALUES ($1, $2, $3, $4, $5, to_timestamp($6::DOUBLE PRECISION))
+	ON CONFLICT DO NOTHING
+	`
+	_, err := q.Exec(query, settlement.MatchupID, settlement.ContractAddress, settlement.Winner, settlement.TxnHash, settlement.LogIndex, settlement.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	WITH settled AS (
+		UPDATE matchups m
+		SET winner = $1, settlement_tx_hash = $2, settled_at = to_timestamp($3::DOUBLE PRECISION), status = $5, updated_at = NOW()
+		FROM (SELECT id, status FROM matchups WHERE id = $4 AND settled_at IS NULL FOR UPDATE) old
+		WHERE m.id = old.id
+		RETURNING m.id, old.status
+	)
+	INSERT INTO matchup_transitions (matchup_id, from_status, to_status, reason)
+	SELECT id, status, $5, 'Settled event indexed' FROM settled WHERE status <> $5
+	`
+	_, err = q.Exec(query, settlement.Winner, settlement.TxnHash, settlement.Timestamp, settlement.MatchupID, MatchupSettled)
+	return err
+}
+
+func insertClaim(q querier, claim *Claim) error {
+	query := `
+	INSERT INTO claims (matchup_id, contract_address, claimer, payout_tinybars, txn_hash, log_index, consensus_at)
+	VALUES ($1, $2, $3, $4, $5, $6, to_timestamp($7::DOUBLE PRECISION))
+	ON CONFLICT DO NOTHING
+	`
+	_, err := q.Exec(query, claim.MatchupID, claim.ContractAddress, claim.Claimer, claim.PayoutTinybars, claim.TxnHash, claim.LogIndex, claim.Timestamp)
+	return err
+}
+
+// markContractVoided records a Voided event. A matchup voided outside this server becomes
+// voided from whatever status it was in, as with settlement.
+func markContractVoided(q querier, void *ContractEvent) error {
+	query := `
+	WITH voided AS (
+		UPDATE matchups m
+		SET status = $1, void_tx_hash = $2,
+			voided_at = COALESCE(m.voided_at, to_timestamp($3::DOUBLE PRECISION)),
+			void_reason = CASE WHEN m.void_reason = '' THEN 'voided on chain' ELSE m.void_reason END,
+			updated_at = NOW()
+		FROM (SELECT id, status FROM matchups WHERE id = $4 AND void_tx_hash = '' FOR UPDATE) old
+		WHERE m.id = old.id
+		RETURNING m.id, old.status
+	)
+	INSERT INTO matchup_transitions (matchup_id, from_status, to_status, reason)
+	SELECT id, status, $1, 'Voided event indexed' FROM voided WHERE status <> $1
+	`
+	_, err := q.Exec(query, MatchupVoided, void.TxnHash, void.Timestamp, void.MatchupID)
+	return err
+}
+
+// upsertRefund completes the refund queued for the bettor, or records one the bettor pulled
+// themselves before it was queued.
+func upsertRefund(q querier, refund *RefundedStake) error {
+	query := `
+	INSERT INTO refunds (matchup_id, contract_address, bettor, amount_tinybars, status, txn_hash, refunded_at, next_attempt_at)
+	VALUES ($1, $2, LOWER($3), $4, $5, $6, to_timestamp($7::DOUBLE PRECISION), NULL)
+	ON CONFLICT (matchup_id, bettor) DO UPDATE
+	SET amount_tinybars = EXCLUDED.amount_tinybars, status = EXCLUDED.status, txn_hash = EXCLUDED.txn_hash,
+		refunded_at = EXCLUDED.refunded_at, next_attempt_at = NULL, last_error = '', updated_at = NOW()
+	`
+	_, err := q.Exec(query, refund.MatchupID, refund.ContractAddress, refund.Bettor, refund.AmountTinybars, RefundRefunded, refund.TxnHash, refund.Timestamp)
+	return err
+}
@@ -17,8 +17,6 @@ func Open() (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
 
-	fmt.Println("DB Connection Established")
-
 	return db, nil
 }
 
@@ -0,0 +1,103 @@
+package stores
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/divin3circle/fplduel/server/migrations"
+)
+
+// defaultTestDBURL is the test_db service from docker-compose.yml.
+const defaultTestDBURL = "host=localhost user=postgres password=postgres dbname=postgres port=5433 sslmode=disable"
+
+// testDB connects to TEST_DB_URL, migrates it and empties every table. Tests that need it are
+// skipped when no database is reachable.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DB_URL")
+	if url == "" {
+		url = defaultTestDBURL
+	}
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Skipf("test db unavailable: %v", err)
+	}
+
+	if err := MigrateFS(db, migrations.FS, "."); err != nil {
+		t.Fatalf("migrate test db: %v", err)
+	}
+	_, err = db.Exec(`TRUNCATE slates, matchups, matchup_transitions, bets, settlements, claims, refunds,
+		contract_cursors, job_runs CASCADE`)
+	if err != nil {
+		t.Fatalf("truncate test db: %v", err)
+	}
+	return db
+}
+
+var testSeq atomic.Int64
+
+// seedSettledMatchup adds a deployed matchup in gameweek settled with winner at settledAt.
+func seedSettledMatchup(t *testing.T, db *sql.DB, gameweek int, winner Outcome, settledAt time.Time) string {
+	t.Helper()
+	return seedMatchup(t, db, gameweek, MatchupSettled, &winner, &settledAt)
+}
+
+func seedMatchup(t *testing.T, db *sql.DB, gameweek int, status string, winner *Outcome, settledAt *time.Time) string {
+	t.Helper()
+	n := testSeq.Add(1)
+	var slateID, id string
+	err := db.QueryRow(`INSERT INTO slates (game_week, status) VALUES ($1, 'retired') RETURNING id`, gameweek).Scan(&slateID)
+	if err != nil {
+		t.Fatalf("insert slate: %v", err)
+	}
+	err = db.QueryRow(`
+	INSERT INTO matchups (slate_id, status, game_week, home_team_id, assigned_home_team_id, away_team_id, assigned_away_team_id,
+		home_team_name, away_team_name, home_team_manager_id, away_team_manager_id, home_team_manager_name, away_team_manager_name,
+		contract_address, deployment_status, winner, settled_at)
+	VALUES ($1, $2, $3, $4, 1, $5, 6, 'Home', 'Away', $4, $5, 'Home Manager', 'Away Manager', $6, $7, $8, $9)
+	RETURNING id
+	`, slateID, status, gameweek, 2*n, 2*n+1, fmt.Sprintf("0x%040x", n), DeploymentDeployed, winner, settledAt).Scan(&id)
+	if err != nil {
+		t.Fatalf("insert matchup: %v", err)
+	}
+	return id
+}
+
+// seedBet adds a bet of tinybars on outcome placed now, confirmed unless pending is set.
+func seedBet(t *testing.T, db *sql.DB, matchupID, bettor string, outcome Outcome, tinybars int64, pending bool) {
+	t.Helper()
+	var verifiedAt *time.Time
+	if !pending {
+		now := time.Now()
+		verifiedAt = &now
+	}
+	insertTestBet(t, db, matchupID, bettor, outcome, tinybars, verifiedAt, time.Now())
+}
+
+// seedPlacedBet adds a confirmed bet whose transaction reached consensus at placedAt. It is
+// recorded now, as a bet submitted late or indexed after the fact would be.
+func seedPlacedBet(t *testing.T, db *sql.DB, matchupID string, outcome Outcome, tinybars int64, placedAt time.Time) {
+	t.Helper()
+	now := time.Now()
+	insertTestBet(t, db, matchupID, "0x00000000000000000000000000000000000b0b00", outcome, tinybars, &now, placedAt)
+}
+
+func insertTestBet(t *testing.T, db *sql.DB, matchupID, bettor string, outcome Outcome, tinybars int64, verifiedAt *time.Time, placedAt time.Time) {
+	t.Helper()
+	_, err := db.Exec(`
+	INSERT INTO bets (user_address, matchup_id, predicted_winner, bet_amount, amount_tinybars, odds, txn_hash,
+		verified_at, outcome_reconciled_at, placed_at)
+	VALUES ($1, $2, $3, $4::NUMERIC / $7, $4, 0, $5, $6, $6, $8)
+	`, bettor, matchupID, outcome, tinybars, fmt.Sprintf("0x%064x", testSeq.Add(1)), verifiedAt, TinybarsPerHbar, placedAt)
+	if err != nil {
+		t.Fatalf("insert bet: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package stores
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+)
+
+// PostgresFPLCacheStore implements fpl.Cache on top of the fpl_cache table.
+type PostgresFPLCacheStore struct {
+	db *sql.DB
+}
+
+func NewPostgresFPLCacheStore(db *sql.DB) *PostgresFPLCacheStore {
+	return &PostgresFPLCacheStore{db: db}
+}
+
+func (pcs *PostgresFPLCacheStore) Get(key string) (*fpl.CacheEntry, error) {
+	entry := &fpl.CacheEntry{}
+	query := `
+	SELECT body, etag, last_modified, expires_at, updated_at
+	FROM fpl_cache
+	WHERE key = $1
+	`
+	err := pcs.db.QueryRow(query, key).Scan(
+		&entry.Body,
+		&entry.ETag,
+		&entry.LastModified,
+		&entry.ExpiresAt,
+		&entry.StoredAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (pcs *PostgresFPLCacheStore) Set(key string, entry *fpl.CacheEntry) error {
+	query := `
+	INSERT INTO fpl_cache (key, body, etag, last_modified, expires_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (key) DO UPDATE SET
+	body = EXCLUDED.body,
+	etag = EXCLUDED.etag,
+	last_modified = EXCLUDED.last_modified,
+	expires_at = EXCLUDED.expires_at,
+	updated_at = EXCLUDED.updated_at
+	`
+	_, err := pcs.db.Exec(query, key, entry.Body, entry.ETag, entry.LastModified, entry.ExpiresAt, entry.StoredAt)
+	return err
+}
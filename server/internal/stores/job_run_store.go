@@ -0,0 +1,117 @@
+package stores
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+	JobRunSkipped   = "skipped"
+)
+
+type JobRun struct {
+	ID         string     `json:"id"`
+	JobName    string     `json:"job_name"`
+	Gameweek   *int       `json:"game_week,omitempty"`
+	Status     string     `json:"status"`
+	Message    string     `json:"message"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type PostgresJobRunStore struct {
+	db *sql.DB
+}
+
+func NewPostgresJobRunStore(db *sql.DB) *PostgresJobRunStore {
+	return &PostgresJobRunStore{db: db}
+}
+
+type JobRunStore interface {
+	StartJobRun(run *JobRun) error
+	FinishJobRun(run *JobRun) error
+	DiscardJobRun(run *JobRun) error
+	ListJobRuns(jobName string, limit int) ([]*JobRun, error)
+	HasSucceeded(jobName string, gameweek int) (bool, error)
+}
+
+func (pjs *PostgresJobRunStore) StartJobRun(run *JobRun) error {
+	query := `
+	INSERT INTO job_runs (job_name, status, started_at)
+	VALUES ($1, $2, NOW())
+	RETURNING id, started_at
+	`
+	run.Status = JobRunRunning
+	return pjs.db.QueryRow(query, run.JobName, run.Status).Scan(&run.ID, &run.StartedAt)
+}
+
+func (pjs *PostgresJobRunStore) FinishJobRun(run *JobRun) error {
+	query := `
+	UPDATE job_runs
+	SET game_week = $1, status = $2, message = $3, error = $4, finished_at = NOW()
+	WHERE id = $5
+	RETURNING finished_at
+	`
+	return pjs.db.QueryRow(query, run.Gameweek, run.Status, run.Message, run.Error, run.ID).Scan(&run.FinishedAt)
+}
+
+// DiscardJobRun deletes a run that turned out to have nothing to do, so idle polling does not
+// fill job_runs.
+func (pjs *PostgresJobRunStore) DiscardJobRun(run *JobRun) error {
+	_, err := pjs.db.Exec(`DELETE FROM job_runs WHERE id = $1`, run.ID)
+	return err
+}
+
+// ListJobRuns returns the most recent runs, optionally filtered by job name.
+func (pjs *PostgresJobRunStore) ListJobRuns(jobName string, limit int) ([]*JobRun, error) {
+	query := `
+	SELECT id, job_name, game_week, status, message, error, started_at, finished_at
+	FROM job_runs
+	WHERE $1 = '' OR job_name = $1
+	ORDER BY started_at DESC
+	LIMIT $2
+	`
+	rows, err := pjs.db.Query(query, jobName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []*JobRun{}
+	for rows.Next() {
+		run := &JobRun{}
+		err := rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.Gameweek,
+			&run.Status,
+			&run.Message,
+			&run.Error,
+			&run.StartedAt,
+			&run.FinishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// HasSucceeded reports whether a job already completed successfully for a gameweek,
+// so once-per-gameweek jobs can skip repeat work.
+func (pjs *PostgresJobRunStore) HasSucceeded(jobName string, gameweek int) (bool, error) {
+	var exists bool
+	query := `
+	SELECT EXISTS(SELECT 1 FROM job_runs WHERE job_name = $1 AND game_week = $2 AND status = $3)
+	`
+	err := pjs.db.QueryRow(query, jobName, gameweek, JobRunSucceeded).Scan(&exists)
+	return exists, err
+}
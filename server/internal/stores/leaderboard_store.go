@@ -0,0 +1,221 @@
+package stores
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Leaderboard orderings.
+const (
+	SortNet     = "net"
+	SortROI     = "roi"
+	SortStaked  = "staked"
+	SortHitRate = "hit_rate"
+	SortStreak  = "streak"
+)
+
+// leaderboardOrder maps each ordering to its SQL, ties broken by net and then address.
+var leaderboardOrder = map[string]string{
+	SortNet:     "net DESC",
+	SortROI:     "(t.returned - t.staked)::NUMERIC / NULLIF(t.staked, 0) DESC",
+	SortStaked:  "staked DESC",
+	SortHitRate: "t.wins::NUMERIC / NULLIF(t.bets, 0) DESC",
+	SortStreak:  "longest_streak DESC",
+}
+
+// BettorStats is one address's record on settled matchups, over the season or a single
+// gameweek. A bet returns stake × netPool / winningPool when it wins and nothing when it
+// loses; bets on voided matchups were refunded and count for nothing.
+type BettorStats struct {
+	Rank             int     `json:"rank,omitempty"`
+	Address          string  `json:"address"`
+	Gameweek         *int    `json:"game_week,omitempty"`
+	Bets             int     `json:"bets"`
+	Wins             int     `json:"wins"`
+	StakedTinybars   int64   `json:"staked_tinybars"`
+	ReturnedTinybars int64   `json:"returned_tinybars"`
+	NetTinybars      int64   `json:"net_tinybars"`
+	ROI              float64 `json:"roi"`
+	HitRate          float64 `json:"hit_rate"`
+	LongestWinStreak int     `json:"longest_win_streak"`
+}
+
+// LeaderboardFilter selects and orders leaderboard rows. A nil Gameweek covers the season.
+type LeaderboardFilter struct {
+	Gameweek *int
+	Sort     string
+	MinBets  int
+	Limit    int
+}
+
+type PostgresLeaderboardStore struct {
+	db *sql.DB
+}
+
+func NewPostgresLeaderboardStore(db *sql.DB) *PostgresLeaderboardStore {
+	return &PostgresLeaderboardStore{db: db}
+}
+
+type LeaderboardStore interface {
+	GetLeaderboard(filter LeaderboardFilter) ([]*BettorStats, error)
+	GetBettorStats(address string) (*BettorStats, error)
+	GetBettorGameweekStats(address string) ([]*BettorStats, error)
+}
+
+// bettorStatsQuery aggregates confirmed bets on settled matchups per key, which is either
+// bettor or bettor and game_week. Returns use the contract's arithmetic in wei: the pool
+// after the 2% fee, shared among the winning pool, floored to tinybars. A win streak is a
+// run of wins in settlement order, broken by any loss.
+//
+// $1 restricts to a gameweek and $2 to an address; either may be NULL. $3 is the minimum
+// number of bets, $4 the row limit, NULL for all, and $5 the settled status. $6, $7 and $8
+// are the home, draw and away outcomes, $9 WeiPerTinybar and $10 FeePercent; queryBettorStats
+// supplies them.
+func bettorStatsQuery(key, order string) string {
+	return fmt.Sprintf(`
+	WITH confirmed AS (
+		SELECT b.id, LOWER(b.user_address) AS bettor, b.matchup_id, b.predicted_winner, b.created_at,
+			b.amount_tinybars::NUMERIC * $9 AS stake_wei,
+			m.game_week, m.status, m.winner, m.settled_at
+		FROM bets b
+		JOIN matchups m ON m.id = b.matchup_id
+		WHERE b.verified_at IS NOT NULL OR b.outcome_reconciled_at IS NOT NULL
+	),
+	pools AS (
+		SELECT matchup_id,
+			SUM(stake_wei) AS total,
+			SUM(stake_wei) FILTER (WHERE predicted_winner = $6) AS home,
+			SUM(stake_wei) FILTER (WHERE predicted_winner = $7) AS draw,
+			SUM(stake_wei) FILTER (WHERE predicted_winner = $8) AS away
+		FROM confirmed
+		GROUP BY matchup_id
+	),
+	resolved AS (
+		SELECT c.bettor, c.game_week, c.id, c.created_at, c.settled_at, c.stake_wei,
+			w.winning > 0 AND c.predicted_winner = c.winner AS won,
+			CASE WHEN w.winning > 0 AND c.predicted_winner = c.winner
+				THEN div(div(c.stake_wei * (p.total - div(p.total * $10, 100)), w.winning), $9)
+				ELSE 0
+			END AS returned
+		FROM confirmed c
+		JOIN pools p ON p.matchup_id = c.matchup_id
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(CASE c.winner WHEN $6 THEN p.home WHEN $7 THEN p.draw ELSE p.away END, 0) AS winning
+		) w
+		WHERE c.status = $5 AND c.winner IS NOT NULL
+			AND ($1::INT IS NULL OR c.game_week = $1)
+			AND ($2::TEXT IS NULL OR c.bettor = LOWER($2))
+	),
+	runs AS (
+		SELECT %[1]s, won,
+			SUM(CASE WHEN won THEN 0 ELSE 1 END) OVER (PARTITION BY %[1]s ORDER BY settled_at, created_at, id) AS run
+		FROM resolved
+	),
+	streaks AS (
+		SELECT %[1]s, MAX(wins) AS longest_streak
+		FROM (SELECT %[1]s, run, COUNT(*) FILTER (WHERE won) AS wins FROM runs GROUP BY %[1]s, run) run_wins
+		GROUP BY %[1]s
+	),
+	totals AS (
+		SELECT %[1]s,
+			COUNT(*) AS bets,
+			COUNT(*) FILTER (WHERE won) AS wins,
+			(SUM(stake_wei) / $9)::BIGINT AS staked,
+			SUM(returned)::BIGINT AS returned
+		FROM resolved
+		GROUP BY %[1]s
+	)
+	SELECT t.*, t.returned - t.staked AS net, s.longest_streak
+	FROM totals t
+	JOIN streaks s USING (%[1]s)
+	WHERE t.bets >= $3
+	ORDER BY %[2]s, net DESC, bettor
+	LIMIT $4
+	`, key, order)
+}
+
+// queryBettorStats runs bettorStatsQuery with its filters followed by the outcome and unit
+// parameters it shares with the contract.
+func (ps *PostgresLeaderboardStore) queryBettorStats(key, order string, gameweek *int, address *string, minBets int, limit *int) (*sql.Rows, error) {
+	return ps.db.Query(bettorStatsQuery(key, order), gameweek, address, minBets, limit, MatchupSettled,
+		OutcomeHome, OutcomeDraw, OutcomeAway, WeiPerTinybar, FeePercent)
+}
+
+// GetLeaderboard ranks bettors by filter.Sort, net profit by default.
+func (ps *PostgresLeaderboardStore) GetLeaderboard(filter LeaderboardFilter) ([]*BettorStats, error) {
+	order, ok := leaderboardOrder[filter.Sort]
+	if !ok {
+		order = leaderboardOrder[SortNet]
+	}
+	var limit *int
+	if filter.Limit > 0 {
+		limit = &filter.Limit
+	}
+
+	rows, err := ps.queryBettorStats("bettor", order, filter.Gameweek, nil, max(filter.MinBets, 1), limit)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := scanBettorStats(rows, false)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range stats {
+		s.Rank = i + 1
+		s.Gameweek = filter.Gameweek
+	}
+	return stats, nil
+}
+
+// GetBettorStats returns an address's season record, with zero counts if none of its bets
+// have been settled.
+func (ps *PostgresLeaderboardStore) GetBettorStats(address string) (*BettorStats, error) {
+	rows, err := ps.queryBettorStats("bettor", leaderboardOrder[SortNet], nil, &address, 1, nil)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := scanBettorStats(rows, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return &BettorStats{Address: address}, nil
+	}
+	return stats[0], nil
+}
+
+// GetBettorGameweekStats returns an address's record in each gameweek it has settled bets in,
+// latest first.
+func (ps *PostgresLeaderboardStore) GetBettorGameweekStats(address string) ([]*BettorStats, error) {
+	rows, err := ps.queryBettorStats("bettor, game_week", "game_week DESC", nil, &address, 1, nil)
+	if err != nil {
+		return nil, err
+	}
+	return scanBettorStats(rows, true)
+}
+
+func scanBettorStats(rows *sql.Rows, byGameweek bool) ([]*BettorStats, error) {
+	defer rows.Close()
+
+	stats := []*BettorStats{}
+	for rows.Next() {
+		s := &BettorStats{}
+		fields := []any{&s.Address}
+		if byGameweek {
+			s.Gameweek = new(int)
+			fields = append(fields, s.Gameweek)
+		}
+		fields = append(fields, &s.Bets, &s.Wins, &s.StakedTinybars, &s.ReturnedTinybars, &s.NetTinybars, &s.LongestWinStreak)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		if s.StakedTinybars > 0 {
+			s.ROI = float64(s.NetTinybars) / float64(s.StakedTinybars)
+		}
+		if s.Bets > 0 {
+			s.HitRate = float64(s.Wins) / float64(s.Bets)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
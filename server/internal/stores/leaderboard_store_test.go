@@ -0,0 +1,122 @@
+package stores
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	alice = "0x00000000000000000000000000000000000a11ce"
+	bob   = "0x0000000000000000000000000000000000000b0b"
+)
+
+// seedLeaderboard settles four matchups in order. Alice wins the first two, loses the third
+// and wins the fourth, each time against an equal stake from Bob, so every win returns the
+// 20 HBAR pool less the fee.
+func seedLeaderboard(t *testing.T) *PostgresLeaderboardStore {
+	db := testDB(t)
+	start := time.Now().Add(-time.Hour)
+	for i, alicePick := range []Outcome{OutcomeHome, OutcomeHome, OutcomeAway, OutcomeHome} {
+		gameweek := 1 + i/2
+		id := seedSettledMatchup(t, db, gameweek, OutcomeHome, start.Add(time.Duration(i)*time.Minute))
+		bobPick := OutcomeAway
+		if alicePick == OutcomeAway {
+			bobPick = OutcomeHome
+			// unconfirmed bets never count
+			seedBet(t, db, id, alice, OutcomeHome, 50*TinybarsPerHbar, true)
+		}
+		seedBet(t, db, id, alice, alicePick, 10*TinybarsPerHbar, false)
+		seedBet(t, db, id, bob, bobPick, 10*TinybarsPerHbar, false)
+	}
+
+	// bets on a voided matchup were refunded
+	voided := seedMatchup(t, db, 2, MatchupVoided, nil, nil)
+	seedBet(t, db, voided, alice, OutcomeDraw, 10*TinybarsPerHbar, false)
+	return NewPostgresLeaderboardStore(db)
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	store := seedLeaderboard(t)
+	const winReturn = 1_960_000_000 // (20 HBAR - 2%) × 10/10
+
+	tests := []struct {
+		sort string
+		want []BettorStats
+	}{
+		{
+			sort: SortNet,
+			want: []BettorStats{
+				{Rank: 1, Address: alice, Bets: 4, Wins: 3, StakedTinybars: 40 * TinybarsPerHbar, ReturnedTinybars: 3 * winReturn,
+					NetTinybars: 3*winReturn - 40*TinybarsPerHbar, ROI: 0.47, HitRate: 0.75, LongestWinStreak: 2},
+				{Rank: 2, Address: bob, Bets: 4, Wins: 1, StakedTinybars: 40 * TinybarsPerHbar, ReturnedTinybars: winReturn,
+					NetTinybars: winReturn - 40*TinybarsPerHbar, ROI: -0.51, HitRate: 0.25, LongestWinStreak: 1},
+			},
+		},
+		{
+			sort: SortStreak,
+			want: []BettorStats{
+				{Rank: 1, Address: alice, LongestWinStreak: 2},
+				{Rank: 2, Address: bob, LongestWinStreak: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			stats, err := store.GetLeaderboard(LeaderboardFilter{Sort: tt.sort})
+			if err != nil {
+				t.Fatalf("GetLeaderboard: %v", err)
+			}
+			if len(stats) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(stats), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := stats[i]
+				if got.Address != want.Address || got.Rank != want.Rank || got.LongestWinStreak != want.LongestWinStreak {
+					t.Errorf("row %d = %s rank %d streak %d, want %s rank %d streak %d",
+						i, got.Address, got.Rank, got.LongestWinStreak, want.Address, want.Rank, want.LongestWinStreak)
+				}
+				if want.Bets == 0 {
+					continue
+				}
+				if got.Bets != want.Bets || got.Wins != want.Wins || got.StakedTinybars != want.StakedTinybars ||
+					got.ReturnedTinybars != want.ReturnedTinybars || got.NetTinybars != want.NetTinybars {
+					t.Errorf("row %d = %+v, want %+v", i, *got, want)
+				}
+				if diff := got.ROI - want.ROI; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("%s ROI = %v, want %v", got.Address, got.ROI, want.ROI)
+				}
+				if got.HitRate != want.HitRate {
+					t.Errorf("%s hit rate = %v, want %v", got.Address, got.HitRate, want.HitRate)
+				}
+			}
+		})
+	}
+}
+
+func TestGetBettorGameweekStats(t *testing.T) {
+	store := seedLeaderboard(t)
+
+	stats, err := store.GetBettorGameweekStats(alice)
+	if err != nil {
+		t.Fatalf("GetBettorGameweekStats: %v", err)
+	}
+	want := []struct{ gameweek, bets, wins, streak int }{
+		{2, 2, 1, 1},
+		{1, 2, 2, 2},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got %d gameweeks, want %d", len(stats), len(want))
+	}
+	for i, w := range want {
+		s := stats[i]
+		if *s.Gameweek != w.gameweek || s.Bets != w.bets || s.Wins != w.wins || s.LongestWinStreak != w.streak {
+			t.Errorf("row %d = GW%d %d bets %d wins streak %d, want GW%d %d bets %d wins streak %d",
+				i, *s.Gameweek, s.Bets, s.Wins, s.LongestWinStreak, w.gameweek, w.bets, w.wins, w.streak)
+		}
+	}
+
+	none, err := store.GetBettorStats("0x000000000000000000000000000000000000dead")
+	if err != nil || none.Bets != 0 {
+		t.Errorf("GetBettorStats of an unknown address = %+v, %v, want zero counts", none, err)
+	}
+}
@@ -0,0 +1,280 @@
+package stores
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Matchup lifecycle. A matchup is scheduled until its contract is deployed, open while it
+// takes bets, locked once betting closes, live while its gameweek is scored, final when the
+// scores are, and settled once its contract pays out. Any matchup that is not settled can
+// be voided instead.
+const (
+	MatchupScheduled = "scheduled"
+	MatchupOpen      = "open"
+	MatchupLocked    = "locked"
+	MatchupLive      = "live"
+	MatchupFinal     = "final"
+	MatchupSettled   = "settled"
+	MatchupVoided    = "voided"
+)
+
+// matchupLifecycle is the order a matchup moves through when nothing goes wrong.
+var matchupLifecycle = []string{MatchupScheduled, MatchupOpen, MatchupLocked, MatchupLive, MatchupFinal, MatchupSettled}
+
+var (
+	ErrInvalidTransition = errors.New("invalid matchup status transition")
+	// ErrMatchupNotScorable is returned when scores are written to a matchup that is not
+	// locked, live or final.
+	ErrMatchupNotScorable = errors.New("matchup scores cannot change in its current status")
+)
+
+// CanTransition reports whether a matchup may move directly from one status to another.
+func CanTransition(from, to string) bool {
+	if to == MatchupVoided {
+		return from != MatchupSettled && from != MatchupVoided
+	}
+	i := slices.Index(matchupLifecycle, from)
+	return i >= 0 && i+1 < len(matchupLifecycle) && matchupLifecycle[i+1] == to
+}
+
+// MatchupTransition is one recorded status change.
+type MatchupTransition struct {
+	ID         int64     `json:"id"`
+	MatchupID  string    `json:"matchup_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AdvanceMatchup moves a matchup forward to status, recording every step in between, e.g.
+// open to live passes through locked. Voiding is a single step. A matchup already at status
+// is left alone. Moving backwards, or opening a matchup, which only MarkDeployed does,
+// returns ErrInvalidTransition.
+func (pm *PostgresMatchupStore) AdvanceMatchup(matchup *Matchup, status, reason string) error {
+	tx, err := pm.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := matchupStatus(tx, matchup.ID)
+	if err != nil {
+		return err
+	}
+	if current == status {
+		matchup.Status = current
+		return nil
+	}
+
+	steps := []string{status}
+	if status != MatchupVoided {
+		from, to := slices.Index(matchupLifecycle, current), slices.Index(matchupLifecycle, status)
+		if from < 1 || to <= from {
+			return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current, status)
+		}
+		steps = matchupLifecycle[from+1 : to+1]
+	}
+	for _, next := range steps {
+		if err := transitionMatchup(tx, matchup.ID, current, next, reason); err != nil {
+			return err
+		}
+		current = next
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	matchup.Status = current
+	return nil
+}
+
+// VoidMatchup cancels a matchup that has not been settled, recording why. Voiding only
+// changes the database; the contract, if there is one, is voided separately and its
+// bettors refunded.
+func (pm *PostgresMatchupStore) VoidMatchup(matchup *Matchup, reason string) error {
+	tx, err := pm.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := matchupStatus(tx, matchup.ID)
+	if err != nil {
+		return err
+	}
+	if err := transitionMatchup(tx, matchup.ID, current, MatchupVoided, reason); err != nil {
+		return err
+	}
+	query := `
+	UPDATE matchups
+	SET void_reason = $1, voided_at = NOW(), updated_at = NOW()
+	WHERE id = $2
+	RETURNING voided_at, updated_at
+	`
+	err = tx.QueryRow(query, reason, matchup.ID).Scan(&matchup.VoidedAt, &matchup.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	matchup.Status = MatchupVoided
+	matchup.VoidReason = reason
+	return nil
+}
+
+// ListUnvoidedContracts returns voided matchups whose deployed contract has not been voided
+// on chain yet and is due another void() attempt. Contracts without void() and those that
+// have failed maxAttempts times are left out.
+func (pm *PostgresMatchupStore) ListUnvoidedContracts(maxAttempts int) ([]*Matchup, error) {
+	query := `
+	SELECT ` + matchupColumns + `
+	FROM matchups
+	WHERE status = $1 AND deployment_status = $2 AND COALESCE(contract_address, '') <> '' AND void_tx_hash = ''
+		AND void_supported AND void_attempts < $3
+		AND (void_next_attempt_at IS NULL OR void_next_attempt_at <= NOW())
+	ORDER BY voided_at
+	`
+	return queryMatchups(pm.db, query, MatchupVoided, DeploymentDeployed, maxAttempts)
+}
+
+// MarkContractVoided records the void() transaction of a voided matchup's contract.
+func (pm *PostgresMatchupStore) MarkContractVoided(id, txHash string) error {
+	query := `
+	UPDATE matchups
+	SET void_tx_hash = $1, void_error = '', void_next_attempt_at = NULL, updated_at = NOW()
+	WHERE id = $2 AND status = $3
+	`
+	_, err := pm.db.Exec(query, txHash, id, MatchupVoided)
+	return err
+}
+
+// MarkContractVoidFailed counts a failed void() attempt and holds the contract back until retryAt.
+func (pm *PostgresMatchupStore) MarkContractVoidFailed(id, reason string, retryAt time.Time) error {
+	query := `
+	UPDATE matchups
+	SET void_attempts = void_attempts + 1, void_error = $1, void_next_attempt_at = $2, updated_at = NOW()
+	WHERE id = $3 AND status = $4
+	`
+	_, err := pm.db.Exec(query, reason, retryAt, id, MatchupVoided)
+	return err
+}
+
+// LockExpiredMatchups locks every open matchup whose betting window has closed and returns
+// how many it locked.
+func (pm *PostgresMatchupStore) LockExpiredMatchups() (int, error) {
+	query := `
+	WITH locked AS (
+		UPDATE matchups
+		SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND betting_ends_at <= NOW()
+		RETURNING id
+	)
+	INSERT INTO matchup_transitions (matchup_id, from_status, to_status, reason)
+	SELECT id, $2, $1, 'betting closed' FROM locked
+	`
+	result, err := pm.db.Exec(query, MatchupLocked, MatchupOpen)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+// ListGameweeksWithoutBettingEnd returns the gameweeks of matchups created before betting ends
+// were stored that still have none, so they can be backfilled from the gameweek deadline.
+func (pm *PostgresMatchupStore) ListGameweeksWithoutBettingEnd() ([]int, error) {
+	rows, err := pm.db.Query(`SELECT DISTINCT game_week FROM matchups WHERE betting_ends_at IS NULL ORDER BY game_week`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	gameweeks := []int{}
+	for rows.Next() {
+		var gameweek int
+		if err := rows.Scan(&gameweek); err != nil {
+			return nil, err
+		}
+		gameweeks = append(gameweeks, gameweek)
+	}
+	return gameweeks, rows.Err()
+}
+
+// BackfillBettingEnd sets the betting end of a gameweek's matchups that have none and returns
+// how many it set.
+func (pm *PostgresMatchupStore) BackfillBettingEnd(gameweek int, bettingEndsAt time.Time) (int, error) {
+	result, err := pm.db.Exec(`
+	UPDATE matchups
+	SET betting_ends_at = $1, updated_at = NOW()
+	WHERE game_week = $2 AND betting_ends_at IS NULL
+	`, bettingEndsAt, gameweek)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+// ListTransitions returns a matchup's status history, oldest first.
+func (pm *PostgresMatchupStore) ListTransitions(matchupID string) ([]*MatchupTransition, error) {
+	query := `
+	SELECT id, matchup_id, from_status, to_status, reason, created_at
+	FROM matchup_transitions
+	WHERE matchup_id = $1
+	ORDER BY id
+	`
+	rows, err := pm.db.Query(query, matchupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []*MatchupTransition{}
+	for rows.Next() {
+		t := &MatchupTransition{}
+		if err := rows.Scan(&t.ID, &t.MatchupID, &t.FromStatus, &t.ToStatus, &t.Reason, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+// transitionMatchup moves a matchup from one status to the next and records it. The update
+// only applies while the matchup is still in from, so concurrent transitions cannot both win.
+func transitionMatchup(q querier, matchupID, from, to, reason string) error {
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	result, err := q.Exec(`UPDATE matchups SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3`, to, matchupID, from)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("%w: matchup %s is no longer %s", ErrInvalidTransition, matchupID, from)
+	}
+
+	_, err = q.Exec(`
+	INSERT INTO matchup_transitions (matchup_id, from_status, to_status, reason)
+	VALUES ($1, $2, $3, $4)
+	`, matchupID, from, to, reason)
+	return err
+}
+
+// matchupStatus reads a matchup's status, locking the row for the rest of the transaction.
+func matchupStatus(q querier, matchupID string) (string, error) {
+	var status string
+	err := q.QueryRow(`SELECT status FROM matchups WHERE id = $1 FOR UPDATE`, matchupID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("matchup %s not found", matchupID)
+	}
+	return status, err
+}
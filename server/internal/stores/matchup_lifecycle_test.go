@@ -0,0 +1,33 @@
+package stores
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{MatchupScheduled, MatchupOpen, true},
+		{MatchupOpen, MatchupLocked, true},
+		{MatchupLocked, MatchupLive, true},
+		{MatchupLive, MatchupFinal, true},
+		{MatchupFinal, MatchupSettled, true},
+		{MatchupOpen, MatchupLive, false},
+		{MatchupLocked, MatchupOpen, false},
+		{MatchupSettled, MatchupFinal, false},
+		{MatchupOpen, MatchupOpen, false},
+		{MatchupScheduled, MatchupVoided, true},
+		{MatchupFinal, MatchupVoided, true},
+		{MatchupSettled, MatchupVoided, false},
+		{MatchupVoided, MatchupVoided, false},
+		{MatchupVoided, MatchupOpen, false},
+		{"unknown", MatchupOpen, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,28 +6,63 @@ import (
 	"time"
 )
 
+const (
+	DeploymentPending   = "pending"
+	DeploymentDeploying = "deploying"
+	DeploymentDeployed  = "deployed"
+	DeploymentFailed    = "failed"
+)
+
 type Matchup struct {
-	ID                  string    `json:"id"`
-	HomeTeamID          int       `json:"home_team_id"`
-	AssignedHomeTeamID  int       `json:"assigned_home_team_id"`
-	AwayTeamID          int       `json:"away_team_id"`
-	AssignedAwayTeamID  int       `json:"assigned_away_team_id"`
-	Gameweek            int       `json:"game_week"`
-	HomeTeamName        string    `json:"home_team_name"`
-	AwayTeamName        string    `json:"away_team_name"`
-	HomeTeamScore       int       `json:"home_team_score"`
-	AwayTeamScore       int       `json:"away_team_score"`
-	HomeTeamManagerID   int       `json:"home_team_manager_id"`
-	AwayTeamManagerID   int       `json:"away_team_manager_id"`
-	HomeTeamManagerName string    `json:"home_team_manager_name"`
-	AwayTeamManagerName string    `json:"away_team_manager_name"`
-	HomeTeamValue       int       `json:"home_team_value"`
-	AwayTeamValue       int       `json:"away_team_value"`
-	HomeTeamTransfers   int       `json:"home_team_transfers"`
-	AwayTeamTransfers   int       `json:"away_team_transfers"`
-	ContractAddress     string    `json:"contract_address"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                      string     `json:"id"`
+	SlateID                 string     `json:"slate_id"`
+	Status                  string     `json:"status"`
+	HomeTeamID              int        `json:"home_team_id"`
+	AssignedHomeTeamID      int        `json:"assigned_home_team_id"`
+	AwayTeamID              int        `json:"away_team_id"`
+	AssignedAwayTeamID      int        `json:"assigned_away_team_id"`
+	Gameweek                int        `json:"game_week"`
+	HomeTeamName            string     `json:"home_team_name"`
+	AwayTeamName            string     `json:"away_team_name"`
+	HomeTeamScore           int        `json:"home_team_score"`
+	AwayTeamScore           int        `json:"away_team_score"`
+	HomeTeamManagerID       int        `json:"home_team_manager_id"`
+	AwayTeamManagerID       int        `json:"away_team_manager_id"`
+	HomeTeamManagerName     string     `json:"home_team_manager_name"`
+	AwayTeamManagerName     string     `json:"away_team_manager_name"`
+	HomeTeamValue           int        `json:"home_team_value"`
+	AwayTeamValue           int        `json:"away_team_value"`
+	HomeTeamTransfers       int        `json:"home_team_transfers"`
+	AwayTeamTransfers       int        `json:"away_team_transfers"`
+	HomeTeamOverallRank     int        `json:"home_team_overall_rank"`
+	AwayTeamOverallRank     int        `json:"away_team_overall_rank"`
+	HomeTeamEventPoints     int        `json:"home_team_event_points"`
+	AwayTeamEventPoints     int        `json:"away_team_event_points"`
+	VirtualPoolHome         int64      `json:"virtual_pool_home"`
+	VirtualPoolDraw         int64      `json:"virtual_pool_draw"`
+	VirtualPoolAway         int64      `json:"virtual_pool_away"`
+	PricingModel            string     `json:"pricing_model"`
+	BettingEndsAt           *time.Time `json:"betting_ends_at"`
+	ContractAddress         string     `json:"contract_address"`
+	DeploymentStatus        string     `json:"deployment_status"`
+	DeploymentTxHash        string     `json:"deployment_tx_hash,omitempty"`
+	DeploymentTxID          string     `json:"deployment_tx_id,omitempty"`
+	DeploymentAttempts      int        `json:"deployment_attempts"`
+	DeploymentNextAttemptAt *time.Time `json:"deployment_next_attempt_at,omitempty"`
+	DeploymentError         string     `json:"deployment_error,omitempty"`
+	Winner                  *Outcome   `json:"winner"`
+	SettlementTxHash        string     `json:"settlement_tx_hash,omitempty"`
+	SettledAt               *time.Time `json:"settled_at,omitempty"`
+	VoidReason              string     `json:"void_reason,omitempty"`
+	VoidedAt                *time.Time `json:"voided_at,omitempty"`
+	VoidTxHash              string     `json:"void_tx_hash,omitempty"`
+	VoidSupported           bool       `json:"void_supported"`
+	VoidAttempts            int        `json:"void_attempts,omitempty"`
+	VoidNextAttemptAt       *time.Time `json:"void_next_attempt_at,omitempty"`
+	VoidError               string     `json:"void_error,omitempty"`
+	RetiredAt               *time.Time `json:"retired_at,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
 }
 
 type PostgresMatchupStore struct {
@@ -44,21 +79,54 @@ type MatchupStore interface {
 	UpdateMatchup(homeTeamScore, awayTeamScore int, matchup *Matchup) error
 	ListMatchups() ([]*Matchup, error)
 	GetGameweekMatchups(gameweek int) ([]*Matchup, error)
+	ClaimPendingDeployments(limit int, lease time.Duration) ([]*Matchup, error)
+	RecordDeploymentSubmitted(id, transactionID string) error
+	MarkDeployed(id, contractAddress, txHash string, bettingEndsAt time.Time) error
+	MarkDeploymentFailed(id, reason string, retryAt *time.Time) error
+	RetryDeployment(id string) (*Matchup, error)
+	RetryFailedDeployments() (int, error)
+	MarkSettled(matchup *Matchup, winner Outcome, txHash string, settledAt time.Time) error
+	ListWatchedContracts(watchPeriod time.Duration) ([]*Matchup, error)
+	AdvanceMatchup(matchup *Matchup, status, reason string) error
+	LockExpiredMatchups() (int, error)
+	ListGameweeksWithoutBettingEnd() ([]int, error)
+	BackfillBettingEnd(gameweek int, bettingEndsAt time.Time) (int, error)
+	ListTransitions(matchupID string) ([]*MatchupTransition, error)
+	VoidMatchup(matchup *Matchup, reason string) error
+	ListUnvoidedContracts(maxAttempts int) ([]*Matchup, error)
+	MarkContractVoided(id, txHash string) error
+	MarkContractVoidFailed(id, reason string, retryAt time.Time) error
 }
 
-func (pm *PostgresMatchupStore) GetMatchupByID(id string) (*Matchup, error) {
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryRow(query string, args ...any) *sql.Row
+	Query(query string, args ...any) (*sql.Rows, error)
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+const matchupColumns = `
+	id, slate_id, status, home_team_id, assigned_home_team_id, away_team_id, assigned_away_team_id, game_week,
+	home_team_name, away_team_name, home_team_score, away_team_score,
+	home_team_manager_id, away_team_manager_id, home_team_manager_name, away_team_manager_name,
+	home_team_value, away_team_value, home_team_transfers, away_team_transfers,
+	home_team_overall_rank, away_team_overall_rank, home_team_event_points, away_team_event_points,
+	virtual_pool_home, virtual_pool_draw, virtual_pool_away, pricing_model, betting_ends_at,
+	COALESCE(contract_address, ''), deployment_status, deployment_tx_hash, deployment_tx_id, deployment_attempts,
+	deployment_next_attempt_at, deployment_error, winner, settlement_tx_hash, settled_at,
+	void_reason, voided_at, void_tx_hash, void_supported, void_attempts, void_next_attempt_at, void_error,
+	retired_at, created_at, updated_at`
+
+func scanMatchup(row rowScanner) (*Matchup, error) {
 	matchup := &Matchup{}
-	query := `
-	SELECT id, home_team_id, assigned_home_team_id, away_team_id, assigned_away_team_id, game_week,
-	       home_team_name, away_team_name, home_team_score, away_team_score,
-	       home_team_manager_id, away_team_manager_id, home_team_manager_name, away_team_manager_name,
-	       home_team_value, away_team_value, home_team_transfers, away_team_transfers,
-	       contract_address, created_at, updated_at
-	FROM matchups
-	WHERE id = $1
-	`
-	err := pm.db.QueryRow(query, id).Scan(
+	err := row.Scan(
 		&matchup.ID,
+		&matchup.SlateID,
+		&matchup.Status,
 		&matchup.HomeTeamID,
 		&matchup.AssignedHomeTeamID,
 		&matchup.AwayTeamID,
@@ -76,10 +144,81 @@ func (pm *PostgresMatchupStore) GetMatchupByID(id string) (*Matchup, error) {
 		&matchup.AwayTeamValue,
 		&matchup.HomeTeamTransfers,
 		&matchup.AwayTeamTransfers,
+		&matchup.HomeTeamOverallRank,
+		&matchup.AwayTeamOverallRank,
+		&matchup.HomeTeamEventPoints,
+		&matchup.AwayTeamEventPoints,
+		&matchup.VirtualPoolHome,
+		&matchup.VirtualPoolDraw,
+		&matchup.VirtualPoolAway,
+		&matchup.PricingModel,
+		&matchup.BettingEndsAt,
 		&matchup.ContractAddress,
+		&matchup.DeploymentStatus,
+		&matchup.DeploymentTxHash,
+		&matchup.DeploymentTxID,
+		&matchup.DeploymentAttempts,
+		&matchup.DeploymentNextAttemptAt,
+		&matchup.DeploymentError,
+		&matchup.Winner,
+		&matchup.SettlementTxHash,
+		&matchup.SettledAt,
+		&matchup.VoidReason,
+		&matchup.VoidedAt,
+		&matchup.VoidTxHash,
+		&matchup.VoidSupported,
+		&matchup.VoidAttempts,
+		&matchup.VoidNextAttemptAt,
+		&matchup.VoidError,
+		&matchup.RetiredAt,
 		&matchup.CreatedAt,
 		&matchup.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return matchup, nil
+}
+
+func queryMatchups(q querier, query string, args ...any) ([]*Matchup, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matchups []*Matchup
+
+	for rows.Next() {
+		matchup, err := scanMatchup(rows)
+		if err != nil {
+			return nil, err
+		}
+		matchups = append(matchups, matchup)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return matchups, nil
+}
+
+func insertMatchup(q querier, matchup *Matchup) error {
+	query := `
+	INSERT INTO matchups (slate_id, home_team_id, assigned_home_team_id, assigned_away_team_id, away_team_id, game_week, home_team_name, away_team_name, home_team_manager_id, away_team_manager_id, home_team_manager_name, away_team_manager_name, home_team_value, away_team_value, home_team_transfers, away_team_transfers, contract_address,
+		home_team_overall_rank, away_team_overall_rank, home_team_event_points, away_team_event_points, virtual_pool_home, virtual_pool_draw, virtual_pool_away, pricing_model, betting_ends_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26)
+	RETURNING id, status, deployment_status, deployment_next_attempt_at, created_at, updated_at
+`
+	return q.QueryRow(query, matchup.SlateID, matchup.HomeTeamID, matchup.AssignedHomeTeamID, matchup.AssignedAwayTeamID, matchup.AwayTeamID, matchup.Gameweek, matchup.HomeTeamName, matchup.AwayTeamName, matchup.HomeTeamManagerID, matchup.AwayTeamManagerID, matchup.HomeTeamManagerName, matchup.AwayTeamManagerName, matchup.HomeTeamValue, matchup.AwayTeamValue, matchup.HomeTeamTransfers, matchup.AwayTeamTransfers, matchup.ContractAddress,
+		matchup.HomeTeamOverallRank, matchup.AwayTeamOverallRank, matchup.HomeTeamEventPoints, matchup.AwayTeamEventPoints, matchup.VirtualPoolHome, matchup.VirtualPoolDraw, matchup.VirtualPoolAway, matchup.PricingModel, matchup.BettingEndsAt).Scan(&matchup.ID, &matchup.Status, &matchup.DeploymentStatus, &matchup.DeploymentNextAttemptAt, &matchup.CreatedAt, &matchup.UpdatedAt)
+}
+
+func (pm *PostgresMatchupStore) GetMatchupByID(id string) (*Matchup, error) {
+	query := `SELECT ` + matchupColumns + `
+	FROM matchups
+	WHERE id = $1
+	`
+	matchup, err := scanMatchup(pm.db.QueryRow(query, id))
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -89,80 +228,235 @@ func (pm *PostgresMatchupStore) GetMatchupByID(id string) (*Matchup, error) {
 	return matchup, nil
 }
 
+// CreateMatchup inserts a single matchup into an existing slate. Whole slates are
+// created atomically through SlateStore.
 func (pm *PostgresMatchupStore) CreateMatchup(matchup *Matchup) error {
-	query := `
-	INSERT INTO matchups (home_team_id, assigned_home_team_id, assigned_away_team_id, away_team_id, game_week, home_team_name, away_team_name, home_team_manager_id, away_team_manager_id, home_team_manager_name, away_team_manager_name, contract_address) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-	RETURNING id, created_at, updated_at
-`
-	err := pm.db.QueryRow(query, matchup.HomeTeamID, matchup.AssignedHomeTeamID, matchup.AssignedAwayTeamID, matchup.AwayTeamID, matchup.Gameweek, matchup.HomeTeamName, matchup.AwayTeamName, matchup.HomeTeamManagerID, matchup.AwayTeamManagerID, matchup.HomeTeamManagerName, matchup.AwayTeamManagerName, matchup.ContractAddress).Scan(&matchup.ID, &matchup.CreatedAt, &matchup.UpdatedAt)
-	return err
+	return insertMatchup(pm.db, matchup)
 }
 
+// UpdateMatchup writes a matchup's scores. Only locked, live and final matchups can be
+// scored; anything else returns ErrMatchupNotScorable.
 func (pm *PostgresMatchupStore) UpdateMatchup(homeTeamScore, awayTeamScore int, matchup *Matchup) error {
 	query := `
 	UPDATE matchups
 	SET home_team_score = $1, away_team_score = $2, updated_at = NOW()
-	WHERE id = $3
+	WHERE id = $3 AND status IN ($4, $5, $6)
 	RETURNING updated_at
 `
-	err := pm.db.QueryRow(query, homeTeamScore, awayTeamScore, matchup.ID).Scan(&matchup.UpdatedAt)
+	err := pm.db.QueryRow(query, homeTeamScore, awayTeamScore, matchup.ID, MatchupLocked, MatchupLive, MatchupFinal).Scan(&matchup.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMatchupNotScorable
+	}
 	return err
 }
 
+// ListMatchups returns every matchup that has not been retired by a slate regeneration.
 func (pm *PostgresMatchupStore) ListMatchups() ([]*Matchup, error) {
-	query := `
-	SELECT id, home_team_id, assigned_home_team_id, away_team_id, assigned_away_team_id, game_week,
-	       home_team_name, away_team_name, home_team_score, away_team_score,
-	       home_team_manager_id, away_team_manager_id, home_team_manager_name, away_team_manager_name,
-	       home_team_value, away_team_value, home_team_transfers, away_team_transfers,
-	       contract_address, created_at, updated_at
+	query := `SELECT ` + matchupColumns + `
 	FROM matchups
+	WHERE retired_at IS NULL
+`
+	return queryMatchups(pm.db, query)
+}
+
+// ListWatchedContracts returns every matchup with a contract, including retired ones, whose
+// contract may still emit events. A contract stops being watched once it is settled and every
+// confirmed winning bettor has claimed, once it is voided and every bettor has been refunded,
+// or once watchPeriod has passed since it was settled or voided.
+func (pm *PostgresMatchupStore) ListWatchedContracts(watchPeriod time.Duration) ([]*Matchup, error) {
+	query := `SELECT ` + matchupColumns + `
+	FROM matchups m
+	WHERE contract_address IS NOT NULL AND contract_address <> ''
+		AND NOT EXISTS (
+			SELECT 1 FROM settlements s
+			WHERE s.contract_address = m.contract_address AND (
+				s.consensus_at <= NOW() - $1 * INTERVAL '1 second'
+				OR NOT EXISTS (
+					SELECT 1 FROM bets b
+					WHERE b.matchup_id = m.id AND b.predicted_winner = s.winner
+						AND (b.verified_at IS NOT NULL OR b.outcome_reconciled_at IS NOT NULL)
+						AND NOT EXISTS (
+							SELECT 1 FROM claims c
+							WHERE c.contract_address = m.contract_address AND LOWER(c.claimer) = LOWER(b.user_address)
+						)
+				)
+			)
+		)
+		AND NOT (void_tx_hash <> '' AND (
+			voided_at <= NOW() - $1 * INTERVAL '1 second'
+			OR NOT EXISTS (
+				SELECT 1 FROM bets b
+				WHERE b.matchup_id = m.id
+					AND (b.verified_at IS NOT NULL OR b.outcome_reconciled_at IS NOT NULL)
+					AND NOT EXISTS (
+						SELECT 1 FROM refunds r
+						WHERE r.matchup_id = m.id AND r.bettor = LOWER(b.user_address) AND r.status = $2
+					)
+			)
+		))
+	ORDER BY created_at
 `
-	rows, err := pm.db.Query(query)
+	return queryMatchups(pm.db, query, watchPeriod.Seconds(), RefundRefunded)
+}
+
+func (pm *PostgresMatchupStore) GetGameweekMatchups(gameweek int) ([]*Matchup, error) {
+	query := `SELECT ` + matchupColumns + `
+	FROM matchups
+	WHERE game_week = $1 AND retired_at IS NULL
+`
+	return queryMatchups(pm.db, query, gameweek)
+}
+
+// ClaimPendingDeployments marks up to limit matchups whose contract is due for deployment as
+// deploying and returns them. Rows locked by another worker are skipped. A claim is a lease:
+// if the worker dies before recording the result, the matchup becomes claimable again once
+// lease has passed.
+func (pm *PostgresMatchupStore) ClaimPendingDeployments(limit int, lease time.Duration) ([]*Matchup, error) {
+	query := `
+	UPDATE matchups
+	SET deployment_status = $1,
+		deployment_attempts = deployment_attempts + 1,
+		deployment_next_attempt_at = NOW() + $2 * INTERVAL '1 second',
+		updated_at = NOW()
+	WHERE id IN (
+		SELECT id FROM matchups
+		WHERE deployment_status IN ($3, $1)
+			AND deployment_next_attempt_at <= NOW()
+			AND status = $5
+		ORDER BY deployment_next_attempt_at
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + matchupColumns
+	return queryMatchups(pm.db, query, DeploymentDeploying, lease.Seconds(), DeploymentPending, limit, MatchupScheduled)
+}
+
+// RecordDeploymentSubmitted stores the transaction ID of a contract create about to be
+// submitted, replacing that of any earlier attempt.
+func (pm *PostgresMatchupStore) RecordDeploymentSubmitted(id, transactionID string) error {
+	_, err := pm.db.Exec(`UPDATE matchups SET deployment_tx_id = $1, updated_at = NOW() WHERE id = $2`, transactionID, id)
+	return err
+}
+
+// MarkDeployed records a deployed contract and the betting end it was deployed with, and
+// opens the matchup for betting.
+func (pm *PostgresMatchupStore) MarkDeployed(id, contractAddress, txHash string, bettingEndsAt time.Time) error {
+	tx, err := pm.db.Begin()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
-
-	var matchups []*Matchup
+	defer tx.Rollback()
 
-	for rows.Next() {
-		var matchup Matchup
-		err := rows.Scan(&matchup.ID, &matchup.HomeTeamID, &matchup.AssignedHomeTeamID, &matchup.AwayTeamID, &matchup.AssignedAwayTeamID, &matchup.Gameweek, &matchup.HomeTeamName, &matchup.AwayTeamName, &matchup.HomeTeamScore, &matchup.AwayTeamScore, &matchup.HomeTeamManagerID, &matchup.AwayTeamManagerID, &matchup.HomeTeamManagerName, &matchup.AwayTeamManagerName, &matchup.HomeTeamValue, &matchup.AwayTeamValue, &matchup.HomeTeamTransfers, &matchup.AwayTeamTransfers, &matchup.ContractAddress, &matchup.CreatedAt, &matchup.UpdatedAt)
-		if err != nil {
-			return nil, err
+	status, err := matchupStatus(tx, id)
+	if err != nil {
+		return err
+	}
+	query := `
+	UPDATE matchups
+	SET deployment_status = $1, contract_address = $2, deployment_tx_hash = $3, betting_ends_at = $4,
+		deployment_error = '', deployment_next_attempt_at = NULL, updated_at = NOW()
+	WHERE id = $5
+	`
+	if _, err := tx.Exec(query, DeploymentDeployed, contractAddress, txHash, bettingEndsAt, id); err != nil {
+		return err
+	}
+	if status == MatchupScheduled {
+		if err := transitionMatchup(tx, id, status, MatchupOpen, "contract deployed"); err != nil {
+			return err
 		}
-		matchups = append(matchups, &matchup)
 	}
-	return matchups, nil
+	return tx.Commit()
 }
 
-func (pm *PostgresMatchupStore) GetGameweekMatchups(gameweek int) ([]*Matchup, error) {
+// BettingOpen reports whether the matchup still takes bets at t. A matchup without a betting
+// end, created before betting ends were stored, has no deadline of its own until one is
+// backfilled from its gameweek; its contract enforces the close.
+func (m *Matchup) BettingOpen(t time.Time) bool {
+	return m.BettingEndsAt == nil || t.Before(*m.BettingEndsAt)
+}
+
+// MarkDeploymentFailed records a failed deployment attempt. With a retryAt the matchup goes back
+// to pending until then; without one it is failed for good until RetryDeployment.
+func (pm *PostgresMatchupStore) MarkDeploymentFailed(id, reason string, retryAt *time.Time) error {
+	status := DeploymentPending
+	if retryAt == nil {
+		status = DeploymentFailed
+	}
 	query := `
-	SELECT id, home_team_id, assigned_home_team_id, away_team_id, assigned_away_team_id, game_week,
-	       home_team_name, away_team_name, home_team_score, away_team_score,
-	       home_team_manager_id, away_team_manager_id, home_team_manager_name, away_team_manager_name,
-	       home_team_value, away_team_value, home_team_transfers, away_team_transfers,
-	       contract_address, created_at, updated_at
-	FROM matchups
-	WHERE game_week = $1
-`
-	rows, err := pm.db.Query(query, gameweek)
+	UPDATE matchups
+	SET deployment_status = $1, deployment_error = $2, deployment_next_attempt_at = $3, updated_at = NOW()
+	WHERE id = $4
+	`
+	_, err := pm.db.Exec(query, status, reason, retryAt, id)
+	return err
+}
+
+// RetryDeployment puts a failed matchup back in the deployment queue with a fresh attempt count.
+// It returns nil if the matchup does not exist, is voided or has not failed.
+func (pm *PostgresMatchupStore) RetryDeployment(id string) (*Matchup, error) {
+	query := `
+	UPDATE matchups
+	SET deployment_status = $1, deployment_attempts = 0, deployment_next_attempt_at = NOW(),
+		deployment_error = '', updated_at = NOW()
+	WHERE id = $2 AND deployment_status = $3 AND status = $4
+	RETURNING ` + matchupColumns
+	matchup, err := scanMatchup(pm.db.QueryRow(query, DeploymentPending, id, DeploymentFailed, MatchupScheduled))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return matchup, nil
+}
 
-	var matchups []*Matchup
+// RetryFailedDeployments requeues every failed, non-voided matchup and returns how many.
+func (pm *PostgresMatchupStore) RetryFailedDeployments() (int, error) {
+	query := `
+	UPDATE matchups
+	SET deployment_status = $1, deployment_attempts = 0, deployment_next_attempt_at = NOW(),
+		deployment_error = '', updated_at = NOW()
+	WHERE deployment_status = $2 AND status = $3
+	`
+	result, err := pm.db.Exec(query, DeploymentPending, DeploymentFailed, MatchupScheduled)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
 
-	for rows.Next() {
-		var matchup Matchup
-		err := rows.Scan(&matchup.ID, &matchup.HomeTeamID, &matchup.AssignedHomeTeamID, &matchup.AwayTeamID, &matchup.AssignedAwayTeamID, &matchup.Gameweek, &matchup.HomeTeamName, &matchup.AwayTeamName, &matchup.HomeTeamScore, &matchup.AwayTeamScore, &matchup.HomeTeamManagerID, &matchup.AwayTeamManagerID, &matchup.HomeTeamManagerName, &matchup.AwayTeamManagerName, &matchup.HomeTeamValue, &matchup.AwayTeamValue, &matchup.HomeTeamTransfers, &matchup.AwayTeamTransfers, &matchup.ContractAddress, &matchup.CreatedAt, &matchup.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		matchups = append(matchups, &matchup)
+// MarkSettled records the on-chain settlement of a final matchup's contract.
+func (pm *PostgresMatchupStore) MarkSettled(matchup *Matchup, winner Outcome, txHash string, settledAt time.Time) error {
+	tx, err := pm.db.Begin()
+	if err != nil {
+		return err
 	}
-	return matchups, nil
+	defer tx.Rollback()
+
+	status, err := matchupStatus(tx, matchup.ID)
+	if err != nil {
+		return err
+	}
+	if err := transitionMatchup(tx, matchup.ID, status, MatchupSettled, "contract settled"); err != nil {
+		return err
+	}
+	query := `
+	UPDATE matchups
+	SET winner = $1, settlement_tx_hash = $2, settled_at = $3, updated_at = NOW()
+	WHERE id = $4
+	RETURNING updated_at
+	`
+	err = tx.QueryRow(query, winner, txHash, settledAt, matchup.ID).Scan(&matchup.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	matchup.Status = MatchupSettled
+	matchup.Winner = &winner
+	matchup.SettlementTxHash = txHash
+	matchup.SettledAt = &settledAt
+	return nil
 }
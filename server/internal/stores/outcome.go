@@ -0,0 +1,54 @@
+package stores
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Outcome is a matchup result as FPLMatchupBet numbers it: bets, counters and settlement
+// all use these values.
+type Outcome int
+
+const (
+	OutcomeHome Outcome = 0
+	OutcomeDraw Outcome = 1
+	OutcomeAway Outcome = 2
+)
+
+var ErrInvalidOutcome = errors.New("outcome must be 0 (home), 1 (draw) or 2 (away)")
+
+func (o Outcome) Valid() bool {
+	return o >= OutcomeHome && o <= OutcomeAway
+}
+
+func (o Outcome) Validate() error {
+	if !o.Valid() {
+		return fmt.Errorf("%w, got %d", ErrInvalidOutcome, int(o))
+	}
+	return nil
+}
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeHome:
+		return "home"
+	case OutcomeDraw:
+		return "draw"
+	case OutcomeAway:
+		return "away"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// OutcomeForScores maps final scores onto an outcome.
+func OutcomeForScores(homeScore, awayScore int) Outcome {
+	switch {
+	case homeScore > awayScore:
+		return OutcomeHome
+	case awayScore > homeScore:
+		return OutcomeAway
+	default:
+		return OutcomeDraw
+	}
+}
@@ -70,6 +70,7 @@ type PlayerStore interface {
 	GetPlayerByCode(code int) (*Player, error)
 	UpdatePlayers(players []*Player) error
 	GetPlayerImageURL(code int) (string, error)
+	GetElementTypes(ids []int) (map[int]int, error)
 }
 
 func (pps *PostgresPlayersStore) GetPlayerByID(id int) (*Player, error) {
@@ -220,6 +221,33 @@ func (pps *PostgresPlayersStore) GetPlayerImageURL(code int) (string, error) {
 	return PlayerImageBaseURL + codeStr + ".png", nil
 }
 
+// GetElementTypes returns the position (1 GK, 2 DEF, 3 MID, 4 FWD) of each player id found.
+func (pps *PostgresPlayersStore) GetElementTypes(ids []int) (map[int]int, error) {
+	query := `
+	SELECT id, element_type
+	FROM players
+	WHERE id = ANY($1)
+	`
+	rows, err := pps.db.Query(query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	elementTypes := make(map[int]int, len(ids))
+	for rows.Next() {
+		var id, elementType int
+		if err := rows.Scan(&id, &elementType); err != nil {
+			return nil, err
+		}
+		elementTypes[id] = elementType
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return elementTypes, nil
+}
+
 func (pps *PostgresPlayersStore) UpdatePlayers(players []*Player) error {
 	tx, err := pps.db.Begin()
 	if err != nil {
@@ -0,0 +1,176 @@
+package stores
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	RefundPending  = "pending"
+	RefundRefunded = "refunded"
+	RefundFailed   = "failed"
+)
+
+// Refund is the return of one bettor's stake from a voided matchup. Bettor is lowercased and
+// AmountTinybars is their total confirmed stake, replaced by the contract's figure once the
+// Refunded event is indexed.
+type Refund struct {
+	ID              string     `json:"id"`
+	MatchupID       string     `json:"matchup_id"`
+	ContractAddress string     `json:"contract_address"`
+	Bettor          string     `json:"bettor"`
+	AmountTinybars  int64      `json:"amount_tinybars"`
+	Status          string     `json:"status"`
+	Attempts        int        `json:"attempts"`
+	NextAttemptAt   *time.Time `json:"next_attempt_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	TxnHash         string     `json:"txn_hash,omitempty"`
+	RefundedAt      *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type PostgresRefundStore struct {
+	db *sql.DB
+}
+
+func NewPostgresRefundStore(db *sql.DB) *PostgresRefundStore {
+	return &PostgresRefundStore{db: db}
+}
+
+type RefundStore interface {
+	CreateRefunds(matchupID string) (int, error)
+	ClaimDueRefunds(limit int, lease time.Duration) ([]*Refund, error)
+	MarkRefunded(id, txnHash string) error
+	MarkRefundFailed(id, reason string, retryAt *time.Time) error
+	GetMatchupRefunds(matchupID string) ([]*Refund, error)
+}
+
+const refundColumns = `
+	id, matchup_id, contract_address, bettor, amount_tinybars, status, attempts, next_attempt_at,
+	last_error, txn_hash, refunded_at, created_at, updated_at`
+
+func scanRefund(row rowScanner) (*Refund, error) {
+	refund := &Refund{}
+	err := row.Scan(
+		&refund.ID,
+		&refund.MatchupID,
+		&refund.ContractAddress,
+		&refund.Bettor,
+		&refund.AmountTinybars,
+		&refund.Status,
+		&refund.Attempts,
+		&refund.NextAttemptAt,
+		&refund.LastError,
+		&refund.TxnHash,
+		&refund.RefundedAt,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+func queryRefunds(q querier, query string, args ...any) ([]*Refund, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := []*Refund{}
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, rows.Err()
+}
+
+// CreateRefunds queues a refund for every bettor with a confirmed stake in a voided matchup
+// and returns how many refunds are queued. It can run again as late bets are indexed: the
+// amounts of refunds not yet paid are brought up to date.
+func (ps *PostgresRefundStore) CreateRefunds(matchupID string) (int, error) {
+	query := `
+	INSERT INTO refunds (matchup_id, contract_address, bettor, amount_tinybars)
+	SELECT b.matchup_id, m.contract_address, LOWER(b.user_address),
+		SUM(b.amount_tinybars)
+	FROM bets b
+	JOIN matchups m ON m.id = b.matchup_id
+	WHERE b.matchup_id = $1 AND m.status = $2 AND COALESCE(m.contract_address, '') <> ''
+		AND (b.verified_at IS NOT NULL OR b.outcome_reconciled_at IS NOT NULL)
+	GROUP BY b.matchup_id, m.contract_address, LOWER(b.user_address)
+	ON CONFLICT (matchup_id, bettor) DO UPDATE
+	SET amount_tinybars = EXCLUDED.amount_tinybars, updated_at = NOW()
+	WHERE refunds.status <> $3 AND refunds.amount_tinybars <> EXCLUDED.amount_tinybars
+	`
+	_, err := ps.db.Exec(query, matchupID, MatchupVoided, RefundRefunded)
+	if err != nil {
+		return 0, err
+	}
+
+	var queued int
+	err = ps.db.QueryRow(`SELECT COUNT(*) FROM refunds WHERE matchup_id = $1 AND status = $2`, matchupID, RefundPending).Scan(&queued)
+	return queued, err
+}
+
+// ClaimDueRefunds takes up to limit pending refunds that are due and whose contract has been
+// voided on chain, counting an attempt on each. Like deployments, a claim is a lease: an
+// unrecorded refund becomes claimable again once lease has passed.
+func (ps *PostgresRefundStore) ClaimDueRefunds(limit int, lease time.Duration) ([]*Refund, error) {
+	query := `
+	UPDATE refunds
+	SET attempts = attempts + 1, next_attempt_at = NOW() + $1 * INTERVAL '1 second', updated_at = NOW()
+	WHERE id IN (
+		SELECT r.id FROM refunds r
+		JOIN matchups m ON m.id = r.matchup_id
+		WHERE r.status = $2 AND r.next_attempt_at <= NOW() AND m.void_tx_hash <> ''
+		ORDER BY r.next_attempt_at
+		LIMIT $3
+		FOR UPDATE OF r SKIP LOCKED
+	)
+	RETURNING ` + refundColumns
+	return queryRefunds(ps.db, query, lease.Seconds(), RefundPending, limit)
+}
+
+// MarkRefunded records the refund transaction of a pending refund.
+func (ps *PostgresRefundStore) MarkRefunded(id, txnHash string) error {
+	query := `
+	UPDATE refunds
+	SET status = $1, txn_hash = $2, refunded_at = NOW(), last_error = '', next_attempt_at = NULL, updated_at = NOW()
+	WHERE id = $3 AND status = $4
+	`
+	_, err := ps.db.Exec(query, RefundRefunded, txnHash, id, RefundPending)
+	return err
+}
+
+// MarkRefundFailed records a failed refund attempt. With a retryAt the refund stays pending
+// until then; without one it is failed for good.
+func (ps *PostgresRefundStore) MarkRefundFailed(id, reason string, retryAt *time.Time) error {
+	status := RefundPending
+	if retryAt == nil {
+		status = RefundFailed
+	}
+	query := `
+	UPDATE refunds
+	SET status = $1, last_error = $2, next_attempt_at = $3, updated_at = NOW()
+	WHERE id = $4 AND status = $5
+	`
+	_, err := ps.db.Exec(query, status, reason, retryAt, id, RefundPending)
+	return err
+}
+
+// GetMatchupRefunds returns a matchup's refunds, largest first.
+func (ps *PostgresRefundStore) GetMatchupRefunds(matchupID string) ([]*Refund, error) {
+	query := `
+	SELECT ` + refundColumns + `
+	FROM refunds
+	WHERE matchup_id = $1
+	ORDER BY amount_tinybars DESC, bettor
+	`
+	return queryRefunds(ps.db, query, matchupID)
+}
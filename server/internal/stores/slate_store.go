@@ -0,0 +1,195 @@
+package stores
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	SlateActive  = "active"
+	SlateRetired = "retired"
+)
+
+var (
+	// ErrSlateExists is returned when a gameweek already has an active slate.
+	ErrSlateExists = errors.New("gameweek already has an active slate")
+	// ErrSlateHasBets is returned when replacing a slate whose matchups have confirmed bets
+	// without forcing it.
+	ErrSlateHasBets = errors.New("active slate has confirmed bets")
+)
+
+// Slate is the set of matchups generated for one gameweek. Only one slate per
+// gameweek is active; regenerating retires the previous one.
+type Slate struct {
+	ID         string     `json:"id"`
+	Gameweek   int        `json:"game_week"`
+	Status     string     `json:"status"`
+	Size       int        `json:"size"`
+	TeamSource string     `json:"team_source"`
+	LeagueID   *int       `json:"league_id,omitempty"`
+	Strategy   string     `json:"strategy"`
+	Seed       *int64     `json:"seed,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at,omitempty"`
+	Matchups   []*Matchup `json:"matchups"`
+}
+
+type PostgresSlateStore struct {
+	db *sql.DB
+}
+
+func NewPostgresSlateStore(db *sql.DB) *PostgresSlateStore {
+	return &PostgresSlateStore{db: db}
+}
+
+type SlateStore interface {
+	GetActiveSlate(gameweek int) (*Slate, error)
+	CreateSlate(slate *Slate) error
+	ReplaceSlate(slate *Slate, force bool) error
+}
+
+// GetActiveSlate returns the gameweek's active slate with its matchups, or nil if there is none.
+func (ps *PostgresSlateStore) GetActiveSlate(gameweek int) (*Slate, error) {
+	query := `
+	SELECT id, game_week, status, size, team_source, league_id, strategy, seed, created_at, retired_at
+	FROM slates
+	WHERE game_week = $1 AND status = $2
+	`
+	slate := &Slate{}
+	err := ps.db.QueryRow(query, gameweek, SlateActive).Scan(&slate.ID, &slate.Gameweek, &slate.Status, &slate.Size, &slate.TeamSource, &slate.LeagueID, &slate.Strategy, &slate.Seed, &slate.CreatedAt, &slate.RetiredAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matchups, err := queryMatchups(ps.db, `SELECT `+matchupColumns+`
+	FROM matchups
+	WHERE slate_id = $1
+	`, slate.ID)
+	if err != nil {
+		return nil, err
+	}
+	slate.Matchups = matchups
+	return slate, nil
+}
+
+// CreateSlate stores a slate and all of its matchups atomically. It returns ErrSlateExists
+// if the gameweek already has an active slate.
+func (ps *PostgresSlateStore) CreateSlate(slate *Slate) error {
+	tx, err := ps.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertSlate(tx, slate); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplaceSlate retires the gameweek's active slate and its matchups, then stores the new
+// slate in the same transaction. Retired matchups are voided, which refunds their bettors, so
+// a slate with confirmed bets is only replaced when force is set; otherwise ReplaceSlate
+// returns ErrSlateHasBets and changes nothing.
+func (ps *PostgresSlateStore) ReplaceSlate(slate *Slate, force bool) error {
+	tx, err := ps.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var retiredID string
+	err = tx.QueryRow(`SELECT id FROM slates WHERE game_week = $1 AND status = $2 FOR UPDATE`, slate.Gameweek, SlateActive).Scan(&retiredID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err == nil {
+		if !force {
+			query := `
+			SELECT EXISTS(
+				SELECT 1
+				FROM bets b
+				JOIN matchups m ON m.id = b.matchup_id
+				WHERE m.slate_id = $1 AND (b.verified_at IS NOT NULL OR b.outcome_reconciled_at IS NOT NULL)
+			)
+			`
+			var hasBets bool
+			if err := tx.QueryRow(query, retiredID).Scan(&hasBets); err != nil {
+				return err
+			}
+			if hasBets {
+				return ErrSlateHasBets
+			}
+		}
+
+		_, err = tx.Exec(`UPDATE slates SET status = $1, retired_at = NOW() WHERE id = $2`, SlateRetired, retiredID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE matchups SET retired_at = NOW(), updated_at = NOW() WHERE slate_id = $1`, retiredID)
+		if err != nil {
+			return err
+		}
+		// a retired matchup will never be played, so any contract it has is voided
+		query := `
+		WITH voided AS (
+			UPDATE matchups m
+			SET status = $2, void_reason = 'slate regenerated', voided_at = NOW()
+			FROM (SELECT id, status FROM matchups WHERE slate_id = $1 AND status NOT IN ($2, $3) FOR UPDATE) old
+			WHERE m.id = old.id
+			RETURNING m.id, old.status
+		)
+		INSERT INTO matchup_transitions (matchup_id, from_status, to_status, reason)
+		SELECT id, status, $2, 'slate regenerated' FROM voided
+		`
+		_, err = tx.Exec(query, retiredID, MatchupVoided, MatchupSettled)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertSlate(tx, slate); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertSlate(q querier, slate *Slate) error {
+	query := `
+	INSERT INTO slates (game_week, status, size, team_source, league_id, strategy, seed)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, status, created_at
+	`
+	err := q.QueryRow(query, slate.Gameweek, SlateActive, slate.Size, slate.TeamSource, slate.LeagueID, slate.Strategy, slate.Seed).Scan(&slate.ID, &slate.Status, &slate.CreatedAt)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+
+	for _, matchup := range slate.Matchups {
+		matchup.SlateID = slate.ID
+		matchup.Gameweek = slate.Gameweek
+		if err := insertMatchup(q, matchup); err != nil {
+			return uniqueViolation(err)
+		}
+	}
+	return nil
+}
+
+// uniqueViolation maps a unique constraint violation onto ErrSlateExists.
+func uniqueViolation(err error) error {
+	if isUniqueViolation(err) {
+		return ErrSlateExists
+	}
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
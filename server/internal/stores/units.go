@@ -0,0 +1,12 @@
+package stores
+
+// Stakes are sent in tinybars and scaled to 18-decimal wei by FPLMatchupBet. Queries that
+// work in HBAR or mirror the contract's arithmetic take these as parameters.
+const (
+	// TinybarsPerHbar is the number of tinybars in one HBAR.
+	TinybarsPerHbar = 100_000_000
+	// WeiPerTinybar is the factor the contract scales msg.value by, and divides payouts by.
+	WeiPerTinybar = 10_000_000_000
+	// FeePercent is the share of the real pool FPLMatchupBet keeps when it is settled.
+	FeePercent = 2
+)
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+)
+
+const (
+	PairingRandom        = "random"
+	PairingValueBalanced = "value"
+	PairingRankBalanced  = "rank"
+	PairingRivalry       = "rivalry"
+	PairingSeeded        = "seeded"
+)
+
+// PairingStrategy decides which teams meet in a slate. The first team of each pair plays at home.
+type PairingStrategy interface {
+	Name() string
+	Pair(ctx context.Context, teams []*fpl.ValuableTeam) ([][2]*fpl.ValuableTeam, error)
+}
+
+// RandomPairing shuffles the teams and pairs the i-th with the (n-1-i)-th. The shuffle is
+// driven by Seed so a slate can be reproduced from the seed stored with it.
+type RandomPairing struct {
+	Seed int64
+}
+
+func (p *RandomPairing) Name() string { return PairingRandom }
+
+func (p *RandomPairing) Pair(ctx context.Context, teams []*fpl.ValuableTeam) ([][2]*fpl.ValuableTeam, error) {
+	if err := checkEven(teams); err != nil {
+		return nil, err
+	}
+	return pairTeams(shuffle(teams, p.Seed)), nil
+}
+
+// SeededPairing is random pairing with a caller-chosen seed, for reproducible slates.
+type SeededPairing struct {
+	RandomPairing
+}
+
+func (p *SeededPairing) Name() string { return PairingSeeded }
+
+// ValueBalancedPairing pairs teams with the closest squad value.
+type ValueBalancedPairing struct{}
+
+func (p *ValueBalancedPairing) Name() string { return PairingValueBalanced }
+
+func (p *ValueBalancedPairing) Pair(ctx context.Context, teams []*fpl.ValuableTeam) ([][2]*fpl.ValuableTeam, error) {
+	if err := checkEven(teams); err != nil {
+		return nil, err
+	}
+	sorted := append([]*fpl.ValuableTeam(nil), teams...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Value != sorted[j].Value {
+			return sorted[i].Value > sorted[j].Value
+		}
+		return sorted[i].EntryID < sorted[j].EntryID
+	})
+	return pairAdjacent(sorted), nil
+}
+
+// RankBalancedPairing pairs teams with the closest overall rank, looked up from FPL.
+type RankBalancedPairing struct {
+	FPL *fpl.Client
+}
+
+func (p *RankBalancedPairing) Name() string { return PairingRankBalanced }
+
+func (p *RankBalancedPairing) Pair(ctx context.Context, teams []*fpl.ValuableTeam) ([][2]*fpl.ValuableTeam, error) {
+	if err := checkEven(teams); err != nil {
+		return nil, err
+	}
+	ranks := make(map[int]int, len(teams))
+	for _, team := range teams {
+		entry, err := p.FPL.GetEntry(ctx, team.EntryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entry %d: %w", team.EntryID, err)
+		}
+		ranks[team.EntryID] = entry.SummaryOverallRank
+	}
+
+	sorted := append([]*fpl.ValuableTeam(nil), teams...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := ranks[sorted[i].EntryID], ranks[sorted[j].EntryID]
+		// unranked entries (rank 0) go last
+		if (ri == 0) != (rj == 0) {
+			return rj == 0
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].EntryID < sorted[j].EntryID
+	})
+	return pairAdjacent(sorted), nil
+}
+
+// RivalryPairing pairs last gameweek's winners against each other and randomly pairs everyone
+// else. If an odd number of winners qualified, the one left over joins the random pool.
+type RivalryPairing struct {
+	Seed            int64
+	PreviousWinners map[int]bool
+}
+
+func (p *RivalryPairing) Name() string { return PairingRivalry }
+
+func (p *RivalryPairing) Pair(ctx context.Context, teams []*fpl.ValuableTeam) ([][2]*fpl.ValuableTeam, error) {
+	if err := checkEven(teams); err != nil {
+		return nil, err
+	}
+	var winners, rest []*fpl.ValuableTeam
+	for _, team := range shuffle(teams, p.Seed) {
+		if p.PreviousWinners[team.EntryID] {
+			winners = append(winners, team)
+		} else {
+			rest = append(rest, team)
+		}
+	}
+	if len(winners)%2 != 0 {
+		rest = append(rest, winners[len(winners)-1])
+		winners = winners[:len(winners)-1]
+	}
+	return append(pairAdjacent(winners), pairTeams(rest)...), nil
+}
+
+func checkEven(teams []*fpl.ValuableTeam) error {
+	if len(teams)%2 != 0 {
+		return fmt.Errorf("cannot pair an odd number of teams: %d", len(teams))
+	}
+	return nil
+}
+
+func shuffle(teams []*fpl.ValuableTeam, seed int64) []*fpl.ValuableTeam {
+	shuffled := append([]*fpl.ValuableTeam(nil), teams...)
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+func pairAdjacent(teams []*fpl.ValuableTeam) [][2]*fpl.ValuableTeam {
+	var pairs [][2]*fpl.ValuableTeam
+
+	for i := 0; i+1 < len(teams); i += 2 {
+		pairs = append(pairs, [2]*fpl.ValuableTeam{teams[i], teams[i+1]})
+	}
+	return pairs
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/divin3circle/fplduel/server/internal/fpl"
+)
+
+const (
+	TeamSourceMostValuable = "most_valuable"
+	TeamSourceLeague       = "league"
+	TeamSourceEntries      = "entries"
+	TeamSourceTopOverall   = "top_overall"
+)
+
+// OverallLeagueID is the classic league every FPL manager belongs to.
+const OverallLeagueID = 314
+
+// MaxMostValuableTeams is how many entries FPL's most valuable teams lists.
+const MaxMostValuableTeams = 10
+
+// TeamSource supplies the teams a slate is drawn from.
+type TeamSource interface {
+	Name() string
+	Teams(ctx context.Context, size int) ([]*fpl.ValuableTeam, error)
+}
+
+// MostValuableSource draws from FPL's most valuable teams, which lists MaxMostValuableTeams entries.
+type MostValuableSource struct {
+	FPL *fpl.Client
+}
+
+func (s *MostValuableSource) Name() string { return TeamSourceMostValuable }
+
+func (s *MostValuableSource) Teams(ctx context.Context, size int) ([]*fpl.ValuableTeam, error) {
+	teams, err := getValuableTeams(ctx, s.FPL)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) < size {
+		return nil, fmt.Errorf("most valuable teams only lists %d teams, need %d", len(teams), size)
+	}
+	return teams[:size], nil
+}
+
+// LeagueSource draws the top entries of a classic league's standings.
+type LeagueSource struct {
+	FPL      *fpl.Client
+	LeagueID int
+}
+
+func (s *LeagueSource) Name() string {
+	if s.LeagueID == OverallLeagueID {
+		return TeamSourceTopOverall
+	}
+	return TeamSourceLeague
+}
+
+func (s *LeagueSource) Teams(ctx context.Context, size int) ([]*fpl.ValuableTeam, error) {
+	var entryIDs []int
+	for page := 1; len(entryIDs) < size; page++ {
+		standings, err := s.FPL.GetClassicLeagueStandings(ctx, s.LeagueID, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get standings of league %d: %w", s.LeagueID, err)
+		}
+		for _, result := range standings.Standings.Results {
+			entryIDs = append(entryIDs, result.Entry)
+		}
+		if !standings.Standings.HasNext {
+			break
+		}
+	}
+	if len(entryIDs) < size {
+		return nil, fmt.Errorf("league %d only has %d entries, need %d", s.LeagueID, len(entryIDs), size)
+	}
+	return getEntryTeams(ctx, s.FPL, entryIDs[:size])
+}
+
+// EntriesSource draws a hand-picked list of entries, in the given order.
+type EntriesSource struct {
+	FPL      *fpl.Client
+	EntryIDs []int
+}
+
+func (s *EntriesSource) Name() string { return TeamSourceEntries }
+
+func (s *EntriesSource) Teams(ctx context.Context, size int) ([]*fpl.ValuableTeam, error) {
+	if len(s.EntryIDs) != size {
+		return nil, fmt.Errorf("got %d entry IDs for a slate of %d teams", len(s.EntryIDs), size)
+	}
+	seen := make(map[int]bool, len(s.EntryIDs))
+	for _, id := range s.EntryIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("entry %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return getEntryTeams(ctx, s.FPL, s.EntryIDs)
+}
+
+// getEntryTeams looks up each entry's name, value and transfers so every source yields the
+// same data as the most valuable teams list.
+func getEntryTeams(ctx context.Context, client *fpl.Client, entryIDs []int) ([]*fpl.ValuableTeam, error) {
+	teams := make([]*fpl.ValuableTeam, 0, len(entryIDs))
+	for _, id := range entryIDs {
+		entry, err := client.GetEntry(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entry %d: %w", id, err)
+		}
+		teams = append(teams, &fpl.ValuableTeam{
+			EntryID:   entry.ID,
+			Name:      entry.Name,
+			Player:    strings.TrimSpace(entry.PlayerFirstName + " " + entry.PlayerLastName),
+			Value:     entry.LastDeadlineValue + entry.LastDeadlineBank,
+			Transfers: entry.LastDeadlineTransfers,
+		})
+	}
+	return teams, nil
+}
@@ -1,14 +1,14 @@
 package utils
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"time"
 
+	"github.com/divin3circle/fplduel/server/internal/fpl"
 	"github.com/divin3circle/fplduel/server/internal/stores"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -16,144 +16,6 @@ import (
 
 type Envelope map[string]any
 
-type DeploymentRequest struct {
-	VirtualPoolA        string  `json:"virtualPoolA"`
-	VirtualPoolDraw     string  `json:"virtualPoolDraw"`
-	VirtualPoolB        string  `json:"virtualPoolB"`
-	BettingEndTimestamp int64  `json:"bettingEndTimestamp"`
-}
-
-type DeploymentResponse struct {
-	Success         bool   `json:"success"`
-	ContractAddress string `json:"contractAddress"`
-	TransactionHash string `json:"transactionHash"`
-	Message         string `json:"message"`
-	BettingEnd      string `json:"bettingEnd"`
-}
-
-type DeploymentResult struct {
-	MatchupID       string
-	ContractAddress string
-	Error           error
-}
-
-type Overrides struct {
-	Rules          map[string]any `json:"rules"`
-	Scoring        map[string]any `json:"scoring"`
-	ElementTypes   []any          `json:"element_types"`
-	PickMultiplier *int           `json:"pick_multiplier"`
-}
-
-type Chip struct {
-	ID         int       `json:"id"`
-	Name       string    `json:"name"`
-	Number     int       `json:"number"`
-	StartEvent int       `json:"start_event"`
-	StopEvent  int       `json:"stop_event"`
-	ChipType   string    `json:"chip_type"`
-	Overrides  Overrides `json:"overrides"`
-}
-
-type Chips []Chip
-
-type BootstrapTeam struct {
-	Code                int     `json:"code"`
-	Draw                int     `json:"draw"`
-	Form                *string `json:"form"`
-	ID                  int     `json:"id"`
-	Loss                int     `json:"loss"`
-	Name                string  `json:"name"`
-	Played              int     `json:"played"`
-	Points              int     `json:"points"`
-	Position            int     `json:"position"`
-	ShortName           string  `json:"short_name"`
-	Strength            int     `json:"strength"`
-	TeamDivision        *string `json:"team_division"`
-	Unavailable         bool    `json:"unavailable"`
-	Win                 int     `json:"win"`
-	StrengthOverallHome int     `json:"strength_overall_home"`
-	StrengthOverallAway int     `json:"strength_overall_away"`
-	StrengthAttackHome  int     `json:"strength_attack_home"`
-	StrengthAttackAway  int     `json:"strength_attack_away"`
-	StrengthDefenceHome int     `json:"strength_defence_home"`
-	StrengthDefenceAway int     `json:"strength_defence_away"`
-	PulseID             int     `json:"pulse_id"`
-}
-
-type BootstrapTeams []BootstrapTeam
-
-type Element struct {
-	ID                         int        `json:"id"`
-	Name                       string     `json:"name"`
-	WebName                    string     `json:"web_name"`
-	TeamID                     int        `json:"team_id"`
-	TeamCode                   int        `json:"team_code"`
-	InDreamteam                bool       `json:"in_dreamteam"`
-	TotalPoints                int        `json:"total_points"`
-	Code                       int        `json:"code"`
-	Photo                      *string    `json:"photo,omitempty"`
-	BirthDate                  *string    `json:"birth_date,omitempty"`
-	TeamJoinedDate             *string    `json:"team_joined_date,omitempty"`
-	MinutesPlayed              int        `json:"minutes_played"`
-	FormRank                   int        `json:"form_rank"`
-	Form                       string     `json:"form"`
-	PointsPerGame              string     `json:"points_per_game"`
-	Influence                  string     `json:"influence"`
-	Creativity                 string     `json:"creativity"`
-	Threat                     string     `json:"threat"`
-	IctIndex                   string     `json:"ict_index"`
-	ElementType                int        `json:"element_type"`
-	TransfersIn                int        `json:"transfers_in"`
-	TransfersOut               int        `json:"transfers_out"`
-	SelectedByPercent          string     `json:"selected_by_percent"`
-	SelectedRank               int        `json:"selected_rank"`
-	PointsPerGameRank          int        `json:"points_per_game_rank"`
-	IctIndexRank               int        `json:"ict_index_rank"`
-	News                       *string    `json:"news,omitempty"`
-	NewsAdded                  *time.Time `json:"news_added,omitempty"`
-	GoalsScored                int        `json:"goals_scored"`
-	Assists                    int        `json:"assists"`
-	CleanSheets                int        `json:"clean_sheets"`
-	GoalsConceded              int        `json:"goals_conceded"`
-	ExpectedGoals              string     `json:"expected_goals"`
-	ExpectedAssists            string     `json:"expected_assists"`
-	ExpectedGoalInvolvements   string     `json:"expected_goal_involvements"`
-	ExpectedGoalsConceded      string     `json:"expected_goals_conceded"`
-	YellowCards                int        `json:"yellow_cards"`
-	RedCards                   int        `json:"red_cards"`
-	DefensiveContributionPer90 float64    `json:"defensive_contribution_per_90"`
-	StartsPer90                float64    `json:"starts_per_90"`
-	Minutes                    int        `json:"minutes"`
-	UpdatedAt                  time.Time  `json:"updated_at"`
-}
-
-type Elements []*Element
-type BootstrapData struct {
-	Chips        *Chips          `json:"chips,omitempty"`
-	TotalPlayers int             `json:"total_players,omitempty"`
-	Teams        *BootstrapTeams `json:"teams,omitempty"`
-	Elements     *Elements       `json:"elements,omitempty"`
-}
-
-type ValuableTeam struct {
-	EntryID   int    `json:"entry"`
-	Name      string `json:"name"`
-	Player    string `json:"player_name"`
-	Value     int    `json:"value_with_bank"`
-	Transfers int    `json:"total_transfers"`
-}
-
-type Status struct {
-	BonusAdded bool   `json:"bonus_added"`
-	Date       string `json:"date"`
-	Event      int    `json:"event"`
-	Points     string `json:"points"`
-}
-type GameweekStatus struct {
-	Status  []Status `json:"status"`
-	Leagues string   `json:"leagues"`
-}
-
 func WriteJSON(w http.ResponseWriter, status int, data Envelope) error {
 	js, err := json.MarshalIndent(data, "", "\t")
 	if err != nil {
@@ -178,92 +40,35 @@ func ReadIDParam(r *http.Request, name string) (string, error) {
 	return idParam, nil
 }
 
-func GetBootstrapData(client *http.Client) (*BootstrapData, error) {
-	url := "https://fantasy.premierleague.com/api/bootstrap-static/"
-	if client == nil {
-		client = http.DefaultClient
-	}
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var data BootstrapData
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, err
-	}
-	return &data, nil
+func GetBootstrapData(ctx context.Context, client *fpl.Client) (*fpl.BootstrapData, error) {
+	return client.GetBootstrapStatic(ctx)
 }
 
-func GetMatchups(client *http.Client) ([]*stores.Matchup, error) {
-	// get the 10 valuable teams
-	top10, err := getValuableTeams(client)
-	if len(top10) != 10 {
-		return nil, fmt.Errorf("invalid top10, hash length: %v.", top10)
+// GetMatchups draws size teams from source and pairs them into matchups for the given gameweek
+// using strategy. The matchups have no contract yet.
+func GetMatchups(ctx context.Context, client *fpl.Client, gameweek int, source TeamSource, size int, strategy PairingStrategy) ([]*stores.Matchup, error) {
+	if size < 2 || size%2 != 0 {
+		return nil, fmt.Errorf("slate size must be a positive even number, got %d", size)
 	}
+	teams, err := source.Teams(ctx, size)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get teams from %s source: %w", source.Name(), err)
 	}
-	// randomize items in the slice and pair n with len - n
-	randomValuableTeams := randomize(top10)
-	pairedTeams := pairTeams(randomValuableTeams)
-	// transform the pairs to meet the Matchup type
-	matchups, err := transformPairs(pairedTeams)
-	if err != nil {
-		return nil, err
+	if len(teams) != size {
+		return nil, fmt.Errorf("%s source returned %d teams, need %d", source.Name(), len(teams), size)
 	}
-
-	for _, matchup := range matchups {
-		contractAddr, err := deployContract()
-		if err != nil {
-			return nil, fmt.Errorf("failed to deploy contract for matchup %s: %v", matchup.ID, err)
-		}
-		matchup.ContractAddress = contractAddr
+	pairedTeams, err := strategy.Pair(ctx, teams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pair teams with %s strategy: %w", strategy.Name(), err)
 	}
+	// transform the pairs to meet the Matchup type
+	matchups := transformPairs(gameweek, pairedTeams)
 
 	return matchups, nil
 }
 
-func deployContract() (string, error) {
-	contractServerURL := "http://localhost:3000/create"
-
-	futureTs := time.Now().UTC().Add( 2 * time.Hour).Unix()
-	deploymentReq := DeploymentRequest{
-		VirtualPoolA:        "100000000000000000000", // 100 HBAR in wei
-		VirtualPoolDraw:     "50000000000000000000",  // 50 HBAR in wei
-		VirtualPoolB:        "100000000000000000000", // 100 HBAR in wei
-		BettingEndTimestamp: futureTs,
-	}
-
-	reqBody, err := json.Marshal(deploymentReq)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal deployment request: %v", err)
-	}
-
-	resp, err := http.Post(contractServerURL, "application/json", bytes.NewBuffer(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to call contract deployment server: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("contract deployment server returned status %d", resp.StatusCode)
-	}
-
-	var deploymentResp DeploymentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&deploymentResp); err != nil {
-		return "", fmt.Errorf("failed to parse deployment response: %v", err)
-	}
-
-	if !deploymentResp.Success {
-		return "", fmt.Errorf("contract deployment failed: %s", deploymentResp.Message)
-	}
-
-	return deploymentResp.ContractAddress, nil
-}
-func GetAllPlayers(client *http.Client) ([]*stores.Player, error){
-	bootstrapData, err := GetBootstrapData(client)
+func GetAllPlayers(ctx context.Context, client *fpl.Client) ([]*stores.Player, error){
+	bootstrapData, err := GetBootstrapData(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -275,55 +80,66 @@ func GetAllPlayers(client *http.Client) ([]*stores.Player, error){
 	return players, nil
 }
 
-func GetCurrentGameweek() (int, error) {
-	url := "https://fantasy.premierleague.com/api/event-status/"
-	client := http.DefaultClient
-
-	resp, err := client.Get(url)
+func GetAllTeams(ctx context.Context, client *fpl.Client) ([]*stores.Team, error) {
+	bootstrapData, err := GetBootstrapData(ctx, client)
 	if err != nil {
-		return -1, err
+		return nil, err
+	}
+	if bootstrapData.Teams == nil {
+		return nil, errors.New("no teams in bootstrap data")
 	}
-	defer resp.Body.Close()
 
-	var data GameweekStatus
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return -1, err
+	var teams []*stores.Team
+	now := time.Now().UTC()
+	for _, t := range *bootstrapData.Teams {
+		teams = append(teams, &stores.Team{
+			ID:        t.ID,
+			Code:      t.Code,
+			Name:      t.Name,
+			ShortName: t.ShortName,
+			Strength:  t.Strength,
+			UpdatedAt: now,
+		})
 	}
-	return data.Status[0].Event + 1, nil
+	return teams, nil
 }
 
-func getValuableTeams(client *http.Client) ([]*ValuableTeam, error) {
-	url := "https://fantasy.premierleague.com/api/stats/most-valuable-teams/"
-	if client == nil {
-		client = http.DefaultClient
+func GetCurrentGameweek(ctx context.Context, client *fpl.Client) (int, error) {
+	data, err := client.GetEventStatus(ctx)
+	if err != nil {
+		return -1, err
 	}
+	if len(data.Status) == 0 {
+		return -1, errors.New("event status has no entries")
+	}
+	return data.Status[0].Event + 1, nil
+}
 
-	resp, err := client.Get(url)
+// GetGameweekDeadline returns the FPL deadline of a gameweek, after which squads are locked.
+func GetGameweekDeadline(ctx context.Context, client *fpl.Client, gameweek int) (time.Time, error) {
+	data, err := client.GetBootstrapStatic(ctx)
 	if err != nil {
-		return nil, err
+		return time.Time{}, err
 	}
-	defer resp.Body.Close()
-
-	var data []*ValuableTeam
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, err
+	if data.Events != nil {
+		for _, event := range *data.Events {
+			if event.ID == gameweek {
+				return event.DeadlineTime, nil
+			}
+		}
 	}
-
-	return data, nil
+	return time.Time{}, fmt.Errorf("gameweek %d not found in bootstrap-static events", gameweek)
 }
 
-func randomize(teams []*ValuableTeam) []*ValuableTeam {
-	rand.Shuffle(len(teams), func(i, j int) {
-		teams[i], teams[j] = teams[j], teams[i]
-	})
-	return teams
+func getValuableTeams(ctx context.Context, client *fpl.Client) ([]*fpl.ValuableTeam, error) {
+	return client.GetMostValuableTeams(ctx)
 }
 
-func pairTeams(teams []*ValuableTeam) [][2]*ValuableTeam {
-	var pairs [][2]*ValuableTeam
+func pairTeams(teams []*fpl.ValuableTeam) [][2]*fpl.ValuableTeam {
+	var pairs [][2]*fpl.ValuableTeam
 
 	for i := 0; i < len(teams)/2; i++ {
-		pair := [2]*ValuableTeam{teams[i], teams[len(teams)-1-i]}
+		pair := [2]*fpl.ValuableTeam{teams[i], teams[len(teams)-1-i]}
 		pairs = append(pairs, pair)
 	}
 	return pairs
@@ -333,19 +149,17 @@ func generateRandomID() string {
 	return uuid.New().String()
 }
 
-func transformPairs(pairs [][2]*ValuableTeam) ([]*stores.Matchup, error) {
-	currentGameWeek, err := GetCurrentGameweek()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current gameweek: %v", err)
-	}
+func transformPairs(gameweek int, pairs [][2]*fpl.ValuableTeam) []*stores.Matchup {
 	var matchups []*stores.Matchup
 	now := time.Now().UTC()
+	// teams are assigned slots 0..n-1, home teams from the front and away teams from the back
+	slots := 2 * len(pairs)
 
 	for idx, pair := range pairs {
 		m := &stores.Matchup{
 			ID:                  generateRandomID(),
 			CreatedAt:           now,
-			Gameweek:            currentGameWeek,
+			Gameweek:            gameweek,
 			HomeTeamID:          pair[0].EntryID,
 			HomeTeamName:        pair[0].Name,
 			HomeTeamManagerName: pair[0].Player,
@@ -359,14 +173,14 @@ func transformPairs(pairs [][2]*ValuableTeam) ([]*stores.Matchup, error) {
 			AwayTeamTransfers:   pair[1].Transfers,
 			AwayTeamScore:       0,
 			AwayTeamValue:       pair[1].Value,
-			AssignedAwayTeamID:  9 - idx,
+			AssignedAwayTeamID:  slots - 1 - idx,
 		}
 		matchups = append(matchups, m)
 	}
-	return matchups, nil
+	return matchups
 }
 
-func transformElementsToPlayers(elements []*Element) []*stores.Player {
+func transformElementsToPlayers(elements []*fpl.Element) []*stores.Player {
 	var players []*stores.Player
 	now := time.Now().UTC()
 
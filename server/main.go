@@ -23,6 +23,7 @@ func main() {
 			panic(err)
 		}
 	}(app.DB)
+	defer app.Scheduler.Stop()
 
 	app.Logger.Println("Application started successfully")
 
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Raw FPL API responses keyed by API path
+CREATE TABLE IF NOT EXISTS fpl_cache (
+    key VARCHAR(255) PRIMARY KEY,
+    body BYTEA NOT NULL,
+    etag VARCHAR(255) NOT NULL DEFAULT '',
+    last_modified VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE, -- NULL means the entry never expires
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fpl_cache;
+-- +goose StatementEnd
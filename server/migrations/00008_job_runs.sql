@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(100) NOT NULL,
+    game_week INT, -- gameweek the run acted on, if any
+    status VARCHAR(20) NOT NULL, -- running, succeeded, failed, skipped
+    message TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS job_runs_job_name_started_at_idx ON job_runs (job_name, started_at DESC);
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS job_runs;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A slate is the set of matchups generated for one gameweek
+CREATE TABLE IF NOT EXISTS slates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    game_week INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, retired
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Only one active slate per gameweek
+CREATE UNIQUE INDEX IF NOT EXISTS slates_active_game_week_idx ON slates (game_week) WHERE status = 'active';
+
+ALTER TABLE matchups ADD COLUMN slate_id UUID REFERENCES slates(id);
+ALTER TABLE matchups ADD COLUMN retired_at TIMESTAMP WITH TIME ZONE;
+
+-- Backfill one slate per gameweek that already has matchups
+INSERT INTO slates (game_week, created_at)
+SELECT game_week, MIN(created_at) FROM matchups GROUP BY game_week;
+
+UPDATE matchups m SET slate_id = s.id FROM slates s WHERE s.game_week = m.game_week;
+
+ALTER TABLE matchups ALTER COLUMN slate_id SET NOT NULL;
+
+-- Retire duplicate pairs created by repeated POST /matchup calls, keeping the earliest
+UPDATE matchups SET retired_at = NOW()
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY game_week, LEAST(home_team_id, away_team_id), GREATEST(home_team_id, away_team_id)
+            ORDER BY created_at
+        ) AS rn
+        FROM matchups
+    ) duplicates
+    WHERE rn > 1
+);
+
+-- A team pair can only meet once per gameweek among live matchups
+CREATE UNIQUE INDEX IF NOT EXISTS matchups_game_week_team_pair_idx
+    ON matchups (game_week, LEAST(home_team_id, away_team_id), GREATEST(home_team_id, away_team_id))
+    WHERE retired_at IS NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS matchups_game_week_team_pair_idx;
+ALTER TABLE matchups DROP COLUMN IF EXISTS retired_at;
+ALTER TABLE matchups DROP COLUMN IF EXISTS slate_id;
+DROP TABLE IF EXISTS slates;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- How the slate's matchups were paired, so a slate can be reproduced
+ALTER TABLE slates ADD COLUMN strategy VARCHAR(20) NOT NULL DEFAULT 'random';
+ALTER TABLE slates ADD COLUMN seed BIGINT;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE slates DROP COLUMN IF EXISTS seed;
+ALTER TABLE slates DROP COLUMN IF EXISTS strategy;
+-- +goose StatementEnd
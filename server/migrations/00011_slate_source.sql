@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Where the slate's teams were drawn from and how many
+ALTER TABLE slates ADD COLUMN size INT NOT NULL DEFAULT 10;
+ALTER TABLE slates ADD COLUMN team_source VARCHAR(20) NOT NULL DEFAULT 'most_valuable';
+ALTER TABLE slates ADD COLUMN league_id INT;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE slates DROP COLUMN IF EXISTS league_id;
+ALTER TABLE slates DROP COLUMN IF EXISTS team_source;
+ALTER TABLE slates DROP COLUMN IF EXISTS size;
+-- +goose StatementEnd
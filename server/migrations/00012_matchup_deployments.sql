@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Matchups are stored before their contract is deployed; a worker picks up pending rows
+ALTER TABLE matchups ADD COLUMN deployment_status VARCHAR(20) NOT NULL DEFAULT 'pending'; -- pending, deploying, deployed, failed
+ALTER TABLE matchups ADD COLUMN deployment_tx_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE matchups ADD COLUMN deployment_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE matchups ADD COLUMN deployment_next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE matchups ADD COLUMN deployment_error TEXT NOT NULL DEFAULT '';
+
+-- Matchups created before the outbox were deployed up front
+UPDATE matchups
+SET deployment_status = 'deployed', deployment_next_attempt_at = NULL
+WHERE contract_address IS NOT NULL AND contract_address <> '';
+
+CREATE INDEX IF NOT EXISTS matchups_deployment_queue_idx
+    ON matchups (deployment_next_attempt_at)
+    WHERE deployment_status IN ('pending', 'deploying') AND retired_at IS NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS matchups_deployment_queue_idx;
+ALTER TABLE matchups DROP COLUMN IF EXISTS deployment_error;
+ALTER TABLE matchups DROP COLUMN IF EXISTS deployment_next_attempt_at;
+ALTER TABLE matchups DROP COLUMN IF EXISTS deployment_attempts;
+ALTER TABLE matchups DROP COLUMN IF EXISTS deployment_tx_hash;
+ALTER TABLE matchups DROP COLUMN IF EXISTS deployment_status;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- On-chain settlement of the matchup contract
+ALTER TABLE matchups ADD COLUMN winner SMALLINT; -- 0 home, 1 draw, 2 away
+ALTER TABLE matchups ADD COLUMN settlement_tx_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE matchups ADD COLUMN settled_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE matchups DROP COLUMN IF EXISTS settled_at;
+ALTER TABLE matchups DROP COLUMN IF EXISTS settlement_tx_hash;
+ALTER TABLE matchups DROP COLUMN IF EXISTS winner;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Outcomes follow the contract: 0 home, 1 draw, 2 away. NOT VALID keeps historic rows
+-- until the reconcile-bet-outcomes job has corrected them from on-chain BetPlaced events.
+--
+-- This migration does not rewrite historic rows itself: the outcome a bettor actually chose
+-- is only recorded in their transaction's BetPlaced event, which SQL cannot read. The
+-- correction runs as the reconcile-bet-outcomes job (services.BetReconciler), which looks up
+-- every bet with a NULL outcome_reconciled_at on the mirror node, rewrites predicted_winner
+-- from the event and sets outcome_reconciled_at. Once no such rows are left, the check can
+-- be enforced with ALTER TABLE bets VALIDATE CONSTRAINT bets_predicted_winner_check.
+ALTER TABLE bets ADD CONSTRAINT bets_predicted_winner_check CHECK (predicted_winner IN (0, 1, 2)) NOT VALID;
+ALTER TABLE matchups ADD CONSTRAINT matchups_winner_check CHECK (winner IN (0, 1, 2));
+
+-- Set once a bet's outcome has been checked against its BetPlaced event
+ALTER TABLE bets ADD COLUMN outcome_reconciled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS bets_unreconciled_idx ON bets (matchup_id) WHERE outcome_reconciled_at IS NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS bets_unreconciled_idx;
+ALTER TABLE bets DROP COLUMN IF EXISTS outcome_reconciled_at;
+ALTER TABLE matchups DROP CONSTRAINT IF EXISTS matchups_winner_check;
+ALTER TABLE bets DROP CONSTRAINT IF EXISTS bets_predicted_winner_check;
+-- +goose StatementEnd
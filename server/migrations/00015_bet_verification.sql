@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Set when a bet was checked against its transaction before being recorded
+ALTER TABLE bets ADD COLUMN verified_at TIMESTAMP WITH TIME ZONE;
+
+-- A transaction backs at most one bet. Older unverified rows may share placeholder hashes,
+-- so uniqueness only covers verified bets.
+CREATE UNIQUE INDEX IF NOT EXISTS bets_txn_hash_key ON bets (LOWER(txn_hash)) WHERE verified_at IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS bets_txn_hash_key;
+ALTER TABLE bets DROP COLUMN IF EXISTS verified_at;
+-- +goose StatementEnd
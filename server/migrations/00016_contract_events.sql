@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Exact stake as sent on chain; bet_amount stays in whole HBAR
+ALTER TABLE bets ADD COLUMN amount_tinybars BIGINT;
+UPDATE bets SET amount_tinybars = bet_amount::BIGINT * 100000000;
+
+CREATE INDEX IF NOT EXISTS bets_user_address_idx ON bets (LOWER(user_address));
+
+-- Settled events, at most one per contract
+CREATE TABLE IF NOT EXISTS settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    matchup_id UUID NOT NULL REFERENCES matchups(id) ON DELETE CASCADE,
+    contract_address VARCHAR(255) NOT NULL,
+    winner SMALLINT NOT NULL CHECK (winner IN (0, 1, 2)),
+    txn_hash VARCHAR(255) NOT NULL,
+    log_index INT NOT NULL,
+    consensus_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (txn_hash, log_index),
+    UNIQUE (contract_address)
+);
+
+-- Claimed events; payouts are in the tinybars actually transferred
+CREATE TABLE IF NOT EXISTS claims (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    matchup_id UUID NOT NULL REFERENCES matchups(id) ON DELETE CASCADE,
+    contract_address VARCHAR(255) NOT NULL,
+    claimer VARCHAR(255) NOT NULL,
+    payout_tinybars BIGINT NOT NULL,
+    txn_hash VARCHAR(255) NOT NULL,
+    log_index INT NOT NULL,
+    consensus_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (txn_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS claims_claimer_idx ON claims (LOWER(claimer));
+
+-- The last log the event indexer has ingested for each contract
+CREATE TABLE IF NOT EXISTS contract_cursors (
+    contract_address VARCHAR(255) PRIMARY KEY,
+    last_timestamp VARCHAR(32) NOT NULL,
+    last_log_index INT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contract_cursors;
+DROP TABLE IF EXISTS claims;
+DROP TABLE IF EXISTS settlements;
+DROP INDEX IF EXISTS bets_user_address_idx;
+ALTER TABLE bets DROP COLUMN IF EXISTS amount_tinybars;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Opening virtual pools in tinybars, and the model and inputs they were priced with.
+-- Defaults are the flat 100/50/100 HBAR seeding every earlier contract was deployed with.
+ALTER TABLE matchups ADD COLUMN virtual_pool_home BIGINT NOT NULL DEFAULT 10000000000;
+ALTER TABLE matchups ADD COLUMN virtual_pool_draw BIGINT NOT NULL DEFAULT 5000000000;
+ALTER TABLE matchups ADD COLUMN virtual_pool_away BIGINT NOT NULL DEFAULT 10000000000;
+ALTER TABLE matchups ADD COLUMN pricing_model VARCHAR(32) NOT NULL DEFAULT 'fixed-v0';
+ALTER TABLE matchups ADD COLUMN home_team_overall_rank INT NOT NULL DEFAULT 0;
+ALTER TABLE matchups ADD COLUMN away_team_overall_rank INT NOT NULL DEFAULT 0;
+ALTER TABLE matchups ADD COLUMN home_team_event_points INT NOT NULL DEFAULT 0;
+ALTER TABLE matchups ADD COLUMN away_team_event_points INT NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE matchups DROP COLUMN IF EXISTS away_team_event_points;
+ALTER TABLE matchups DROP COLUMN IF EXISTS home_team_event_points;
+ALTER TABLE matchups DROP COLUMN IF EXISTS away_team_overall_rank;
+ALTER TABLE matchups DROP COLUMN IF EXISTS home_team_overall_rank;
+ALTER TABLE matchups DROP COLUMN IF EXISTS pricing_model;
+ALTER TABLE matchups DROP COLUMN IF EXISTS virtual_pool_away;
+ALTER TABLE matchups DROP COLUMN IF EXISTS virtual_pool_draw;
+ALTER TABLE matchups DROP COLUMN IF EXISTS virtual_pool_home;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- When the matchup's contract stops taking bets: the gameweek deadline plus a configured
+-- offset. Earlier matchups get theirs when their contract is deployed.
+ALTER TABLE matchups ADD COLUMN betting_ends_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE matchups DROP COLUMN IF EXISTS betting_ends_at;
+-- +goose StatementEnd
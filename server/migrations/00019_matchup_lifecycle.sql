@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE matchups ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'scheduled'
+    CHECK (status IN ('scheduled', 'open', 'locked', 'live', 'final', 'settled', 'voided'));
+
+UPDATE matchups SET status = CASE
+    WHEN settled_at IS NOT NULL THEN 'settled'
+    WHEN retired_at IS NOT NULL THEN 'voided'
+    WHEN COALESCE(contract_address, '') = '' THEN 'scheduled'
+    WHEN betting_ends_at IS NULL OR betting_ends_at > NOW() THEN 'open'
+    ELSE 'locked'
+END;
+
+CREATE INDEX IF NOT EXISTS matchups_status_idx ON matchups (status);
+
+-- Every status change of a matchup, oldest first by id
+CREATE TABLE IF NOT EXISTS matchup_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    matchup_id UUID NOT NULL REFERENCES matchups(id) ON DELETE CASCADE,
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS matchup_transitions_matchup_idx ON matchup_transitions (matchup_id, id);
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS matchup_transitions;
+DROP INDEX IF EXISTS matchups_status_idx;
+ALTER TABLE matchups DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Why and when a matchup was voided, and the void() transaction on its contract if it has one
+ALTER TABLE matchups ADD COLUMN void_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE matchups ADD COLUMN voided_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE matchups ADD COLUMN void_tx_hash VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE matchups SET voided_at = COALESCE(retired_at, updated_at), void_reason = 'slate regenerated'
+WHERE status = 'voided';
+
+-- One refund per bettor of a voided matchup; bettor is stored lowercased
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    matchup_id UUID NOT NULL REFERENCES matchups(id) ON DELETE CASCADE,
+    contract_address VARCHAR(255) NOT NULL,
+    bettor VARCHAR(255) NOT NULL,
+    amount_tinybars BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'refunded', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    txn_hash VARCHAR(255) NOT NULL DEFAULT '',
+    refunded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (matchup_id, bettor)
+);
+
+CREATE INDEX IF NOT EXISTS refunds_due_idx ON refunds (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS refunds_bettor_idx ON refunds (bettor);
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE matchups DROP COLUMN IF EXISTS void_tx_hash;
+ALTER TABLE matchups DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE matchups DROP COLUMN IF EXISTS void_reason;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Skipped runs are no longer kept; drop the ones recorded before
+DELETE FROM job_runs WHERE status = 'skipped';
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+-- deleted runs cannot be restored
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Hedera transaction ID of the latest contract create, recorded before it is submitted so a
+-- deployment whose result was lost can be found on the mirror node instead of redeployed
+ALTER TABLE matchups ADD COLUMN deployment_tx_id VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE matchups DROP COLUMN IF EXISTS deployment_tx_id;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Bets submitted through the API under a Hedera transaction ID were indexed a second time
+-- under their Ethereum hash. Pair each such bet with the indexed copy of the same stake,
+-- keep the API row, which has the quoted odds, under the hash and drop the copy.
+CREATE TEMP TABLE bet_duplicates AS
+WITH api AS (
+    SELECT id, matchup_id, LOWER(user_address) AS bettor, predicted_winner,
+        COALESCE(amount_tinybars, bet_amount::BIGINT * 100000000) AS stake,
+        ROW_NUMBER() OVER (PARTITION BY matchup_id, LOWER(user_address), predicted_winner,
+            COALESCE(amount_tinybars, bet_amount::BIGINT * 100000000) ORDER BY created_at, id) AS n
+    FROM bets
+    WHERE txn_hash NOT LIKE '0x%' AND verified_at IS NOT NULL
+),
+indexed AS (
+    SELECT id, matchup_id, LOWER(user_address) AS bettor, predicted_winner, amount_tinybars AS stake,
+        txn_hash, outcome_reconciled_at,
+        ROW_NUMBER() OVER (PARTITION BY matchup_id, LOWER(user_address), predicted_winner, amount_tinybars
+            ORDER BY created_at, id) AS n
+    FROM bets
+    WHERE txn_hash LIKE '0x%' AND odds = 0 AND verified_at = outcome_reconciled_at
+)
+SELECT api.id AS api_id, indexed.id AS indexed_id, indexed.txn_hash, indexed.stake, indexed.outcome_reconciled_at
+FROM api
+JOIN indexed ON indexed.matchup_id = api.matchup_id AND indexed.bettor = api.bettor
+    AND indexed.predicted_winner = api.predicted_winner AND indexed.stake = api.stake AND indexed.n = api.n;
+
+DELETE FROM bets WHERE id IN (SELECT indexed_id FROM bet_duplicates);
+
+UPDATE bets b
+SET txn_hash = d.txn_hash, amount_tinybars = d.stake,
+    outcome_reconciled_at = COALESCE(b.outcome_reconciled_at, d.outcome_reconciled_at), updated_at = NOW()
+FROM bet_duplicates d
+WHERE b.id = d.api_id;
+
+DROP TABLE bet_duplicates;
+
+-- Stakes are kept exactly: amount_tinybars always, and bet_amount in HBAR with the
+-- fraction indexed bets under 1 HBAR used to lose
+UPDATE bets SET amount_tinybars = bet_amount::BIGINT * 100000000 WHERE amount_tinybars IS NULL;
+ALTER TABLE bets ALTER COLUMN amount_tinybars SET NOT NULL;
+ALTER TABLE bets ALTER COLUMN bet_amount TYPE NUMERIC(20, 8);
+UPDATE bets SET bet_amount = amount_tinybars / 100000000.0;
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE bets ALTER COLUMN bet_amount TYPE INT USING TRUNC(bet_amount);
+ALTER TABLE bets ALTER COLUMN amount_tinybars DROP NOT NULL;
+-- +goose StatementEnd
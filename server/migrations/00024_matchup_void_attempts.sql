@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- void() attempts on a voided matchup's contract, retried with backoff like deployments.
+-- void_supported is false for contracts deployed before void() existed, which always revert.
+ALTER TABLE matchups ADD COLUMN void_supported BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE matchups ADD COLUMN void_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE matchups ADD COLUMN void_next_attempt_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE matchups ADD COLUMN void_error TEXT NOT NULL DEFAULT '';
+
+-- Contracts created before the refunds migration was applied were built without void()
+UPDATE matchups
+SET void_supported = FALSE, void_error = 'contract predates void(); refund bettors manually'
+WHERE COALESCE(contract_address, '') <> ''
+    AND created_at < (SELECT MIN(tstamp) FROM goose_db_version WHERE version_id = 20 AND is_applied);
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE matchups DROP COLUMN IF EXISTS void_error;
+ALTER TABLE matchups DROP COLUMN IF EXISTS void_next_attempt_at;
+ALTER TABLE matchups DROP COLUMN IF EXISTS void_attempts;
+ALTER TABLE matchups DROP COLUMN IF EXISTS void_supported;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Consensus time of the bet's transaction. Bets indexed from the chain were created at their
+-- consensus time; API bets were created on submission, shortly after it.
+ALTER TABLE bets ADD COLUMN placed_at TIMESTAMP WITH TIME ZONE;
+UPDATE bets SET placed_at = created_at WHERE verified_at IS NOT NULL OR outcome_reconciled_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS bets_matchup_placed_at_idx ON bets (matchup_id, placed_at);
+
+-- +goose StatementEnd
+-- +goose Down
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS bets_matchup_placed_at_idx;
+ALTER TABLE bets DROP COLUMN IF EXISTS placed_at;
+-- +goose StatementEnd